@@ -0,0 +1,231 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// AssignmentTagPrefix ライセンスの割り当て先を表すタグの接頭辞
+//
+// 割り当て先は"assigned-to=<パーティションのID>/<クライアントのID>"の形のタグとしてライセンスに記録する。
+const AssignmentTagPrefix = "assigned-to="
+
+// Assignment ライセンスとクライアントの対応
+type Assignment struct {
+	LicenseID   LicenseID
+	PartitionID PartitionID
+	ClientID    ClientID
+}
+
+// UnlicensedClient ライセンスが割り当てられていないクライアント
+type UnlicensedClient struct {
+	PartitionID   PartitionID
+	PartitionName string
+	ClientID      ClientID
+	ClientName    string
+}
+
+// ErrLicenseAssigned ライセンスが既に別のクライアントに割り当てられていることを表す
+var ErrLicenseAssigned = errors.New("license already assigned")
+
+// AssignmentTag 割り当て先を表すタグ
+func AssignmentTag(partitionID PartitionID, clientID ClientID) string {
+	return AssignmentTagPrefix + string(partitionID) + "/" + string(clientID)
+}
+
+// ParseAssignment タグから割り当て先を取り出す。割り当てられていなければfalse
+func ParseAssignment(tags []string) (partitionID PartitionID, clientID ClientID, ok bool) {
+	for _, t := range tags {
+		if v, found := strings.CutPrefix(t, AssignmentTagPrefix); found {
+			if p, c, ok := strings.Cut(v, "/"); ok {
+				return PartitionID(p), ClientID(c), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+type AssignmentAPI interface {
+	Assign(ctx context.Context, licenseID LicenseID, partitionID PartitionID, clientID ClientID) error
+	Release(ctx context.Context, licenseID LicenseID) error
+	List(ctx context.Context) ([]Assignment, error)
+	Unassigned(ctx context.Context) ([]License, error)
+	UnlicensedClients(ctx context.Context) ([]UnlicensedClient, error)
+}
+
+var _ AssignmentAPI = (*AssignmentOp)(nil)
+
+// AssignmentOp ライセンスのタグを用いてライセンスとクライアントの対応を管理する
+type AssignmentOp struct {
+	client   *v1.Client
+	licenses LicenseAPI
+}
+
+func NewAssignmentOp(client *v1.Client) AssignmentAPI {
+	return &AssignmentOp{client: client, licenses: NewLicenseOp(client)}
+}
+
+// Assign ライセンスをクライアントに割り当てる
+//
+// クライアントが存在することを確かめる。既に同じクライアントに割り当てられていれば何もしない。
+// 別のクライアントに割り当てられていればErrLicenseAssignedを返す。
+//
+// タグの読み出しから更新までは排他されない。同時に別の割り当てが行われた場合に備えて更新後に読み直し、
+// 割り当てが上書きされていればErrLicenseAssignedを返すが、読み直した後の上書きは検出できない。
+func (op *AssignmentOp) Assign(ctx context.Context, licenseID LicenseID, partitionID PartitionID, clientID ClientID) error {
+	// 古いタグで上書きしないよう、常に最新の状態を読む
+	ctx = BypassCache(ctx)
+	lic, err := op.licenses.Read(ctx, licenseID)
+	if err != nil {
+		return err
+	}
+	if p, c, ok := ParseAssignment(lic.Tags); ok {
+		if p == partitionID && c == clientID {
+			return nil
+		}
+		return NewError("Assignment.Assign", fmt.Errorf("%w: %s is assigned to %s/%s", ErrLicenseAssigned, licenseID, p, c))
+	}
+
+	hsm, err := NewCloudHSMOp(op.client).Read(ctx, partitionID)
+	if err != nil {
+		return err
+	}
+	clientOp, err := NewClientOp(op.client, hsm)
+	if err != nil {
+		return NewError("Assignment.Assign", err)
+	}
+	if _, err := clientOp.Read(ctx, clientID); err != nil {
+		return err
+	}
+
+	if err := op.updateTags(ctx, lic, append(slices.Clone(lic.Tags), AssignmentTag(partitionID, clientID))); err != nil {
+		return err
+	}
+
+	lic, err = op.licenses.Read(ctx, licenseID)
+	if err != nil {
+		return err
+	}
+	if p, c, ok := ParseAssignment(lic.Tags); !ok || p != partitionID || c != clientID {
+		return NewError("Assignment.Assign", fmt.Errorf("%w: %s was reassigned concurrently", ErrLicenseAssigned, licenseID))
+	}
+	return nil
+}
+
+// Release ライセンスの割り当てを解除する。割り当てられていなければ何もしない
+func (op *AssignmentOp) Release(ctx context.Context, licenseID LicenseID) error {
+	ctx = BypassCache(ctx)
+	lic, err := op.licenses.Read(ctx, licenseID)
+	if err != nil {
+		return err
+	}
+	tags := slices.DeleteFunc(slices.Clone(lic.Tags), func(t string) bool {
+		return strings.HasPrefix(t, AssignmentTagPrefix)
+	})
+	if len(tags) == len(lic.Tags) {
+		return nil
+	}
+	return op.updateTags(ctx, lic, tags)
+}
+
+func (op *AssignmentOp) updateTags(ctx context.Context, lic *License, tags []string) error {
+	_, err := op.licenses.Update(ctx, lic.ID, CloudHSMSoftwareLicenseUpdateParams{
+		Name:        lic.Name,
+		Description: lic.Description,
+		Tags:        tags,
+	})
+	return err
+}
+
+// List 割り当てられているライセンスの一覧
+func (op *AssignmentOp) List(ctx context.Context) ([]Assignment, error) {
+	licenses, err := op.licenses.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Assignment
+	for _, l := range licenses {
+		if p, c, ok := ParseAssignment(l.Tags); ok {
+			ret = append(ret, Assignment{LicenseID: l.ID, PartitionID: p, ClientID: c})
+		}
+	}
+	return ret, nil
+}
+
+// Unassigned どのクライアントにも割り当てられていないライセンスの一覧
+func (op *AssignmentOp) Unassigned(ctx context.Context) ([]License, error) {
+	licenses, err := op.licenses.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(licenses, func(l License) bool {
+		_, _, ok := ParseAssignment(l.Tags)
+		return ok
+	}), nil
+}
+
+// UnlicensedClients ライセンスが割り当てられていないクライアントの一覧
+//
+// クライアントは利用可能(available)なパーティションのものに限る。
+func (op *AssignmentOp) UnlicensedClients(ctx context.Context) ([]UnlicensedClient, error) {
+	assignments, err := op.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assigned := map[Assignment]bool{}
+	for _, a := range assignments {
+		assigned[Assignment{PartitionID: a.PartitionID, ClientID: a.ClientID}] = true
+	}
+
+	hsms, err := NewCloudHSMOp(op.client).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []UnlicensedClient
+	for i := range hsms {
+		hsm := &hsms[i]
+		if !hsm.Available() {
+			continue
+		}
+		clientOp, err := NewClientOp(op.client, hsm)
+		if err != nil {
+			return nil, NewError("Assignment.UnlicensedClients", err)
+		}
+		clients, err := clientOp.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range clients {
+			if !assigned[Assignment{PartitionID: hsm.ID, ClientID: c.ID}] {
+				ret = append(ret, UnlicensedClient{
+					PartitionID:   hsm.ID,
+					PartitionName: hsm.Name,
+					ClientID:      c.ID,
+					ClientName:    c.Name,
+				})
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// assignmentHandler serves licenses from store and a single available
+// partition "hsm-1" with clients "client-1" and "client-2".
+func assignmentHandler(store *licenseStore) http.Handler {
+	hsm := TemplateCloudHSM
+	hsm.SetID("hsm-1")
+	hsm.SetName("hsm")
+	var clients []v1.CloudHSMClient
+	for _, id := range []string{"client-1", "client-2"} {
+		c := TemplateCloudHSMClient
+		c.SetID(id)
+		c.SetName(id)
+		clients = append(clients, c)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := strings.TrimSuffix(r.URL.Path, "/"); {
+		case strings.Contains(p, "/licenses"):
+			store.ServeHTTP(w, r)
+		case strings.HasSuffix(p, "/cloudhsms"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMList{
+				Count: 1, From: v1.NewOptInt(0), Total: v1.NewOptInt(1), CloudHSMs: []v1.CloudHSM{hsm},
+			})
+		case strings.HasSuffix(p, "/cloudhsms/hsm-1"):
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSM{CloudHSM: hsm})
+		case strings.HasSuffix(p, "/clients"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMClientList{
+				Count: len(clients), From: v1.NewOptInt(0), Total: v1.NewOptInt(len(clients)), Clients: clients,
+			})
+		case strings.HasSuffix(p, "/clients/client-1"):
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSMClient{Client: clients[0]})
+		default:
+			respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+		}
+	})
+}
+
+func TestParseAssignment(t *testing.T) {
+	assert := require.New(t)
+
+	p, c, ok := ParseAssignment([]string{"env=prod", AssignmentTag("hsm-1", "client-1")})
+	assert.True(ok)
+	assert.Equal(PartitionID("hsm-1"), p)
+	assert.Equal(ClientID("client-1"), c)
+
+	_, _, ok = ParseAssignment([]string{"env=prod", AssignmentTagPrefix + "broken"})
+	assert.False(ok)
+}
+
+func TestAssignmentOp(t *testing.T) {
+	assert := require.New(t)
+	store := &licenseStore{}
+	lic1 := LicenseID(store.add("lic-a", "env=prod"))
+	lic2 := LicenseID(store.add("lic-b"))
+	api := NewAssignmentOp(newTestClientWithHandler(t, assignmentHandler(store)))
+	ctx := context.Background()
+
+	assert.NoError(api.Assign(ctx, lic1, "hsm-1", "client-1"))
+	// Assigning to the same client again is a no-op.
+	assert.NoError(api.Assign(ctx, lic1, "hsm-1", "client-1"))
+
+	err := api.Assign(ctx, lic1, "hsm-1", "client-2")
+	assert.True(errors.Is(err, ErrLicenseAssigned))
+	// Unknown clients are rejected before the license is touched.
+	assert.Error(api.Assign(ctx, lic2, "hsm-1", "client-9"))
+
+	assignments, err := api.List(ctx)
+	assert.NoError(err)
+	assert.Equal([]Assignment{{LicenseID: lic1, PartitionID: "hsm-1", ClientID: "client-1"}}, assignments)
+
+	unassigned, err := api.Unassigned(ctx)
+	assert.NoError(err)
+	assert.Len(unassigned, 1)
+	assert.Equal(lic2, unassigned[0].ID)
+
+	unlicensed, err := api.UnlicensedClients(ctx)
+	assert.NoError(err)
+	assert.Equal([]UnlicensedClient{{PartitionID: "hsm-1", PartitionName: "hsm", ClientID: "client-2", ClientName: "client-2"}}, unlicensed)
+
+	assert.NoError(api.Release(ctx, lic1))
+	assert.NoError(api.Release(ctx, lic1))
+	lic, err := NewLicenseOp(newTestClientWithHandler(t, store)).Read(ctx, lic1)
+	assert.NoError(err)
+	assert.Equal([]string{"env=prod"}, lic.Tags)
+	assert.Equal("lic-a", lic.Name)
+
+	unlicensed, err = api.UnlicensedClients(ctx)
+	assert.NoError(err)
+	assert.Len(unlicensed, 2)
+}
+
+func TestAssignmentOp_StaleCache(t *testing.T) {
+	assert := require.New(t)
+	store := &licenseStore{}
+	id := store.add("lic-a")
+	client := newTestClientWithHandler(t, assignmentHandler(store), WithCache(NewCache(CacheOptions{TTL: time.Hour})))
+	api := NewAssignmentOp(client)
+	ctx := context.Background()
+
+	// cache the license before someone else assigns it
+	_, err := NewLicenseOp(client).Read(ctx, LicenseID(id))
+	assert.NoError(err)
+	store.mu.Lock()
+	store.licenses[store.find(id)].SetTags([]string{AssignmentTag("hsm-1", "client-2")})
+	store.mu.Unlock()
+
+	err = api.Assign(ctx, LicenseID(id), "hsm-1", "client-1")
+	assert.ErrorIs(err, ErrLicenseAssigned)
+	assert.Equal([]string{AssignmentTag("hsm-1", "client-2")}, store.licenses[store.find(id)].Tags)
+}
+
+func TestAssignmentOp_ConcurrentAssign(t *testing.T) {
+	assert := require.New(t)
+	store := &licenseStore{}
+	id := store.add("lic-a")
+	handler := assignmentHandler(store)
+	// another writer assigns the license right after our update is applied
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		if r.Method == http.MethodPut {
+			store.mu.Lock()
+			store.licenses[store.find(id)].SetTags([]string{AssignmentTag("hsm-1", "client-2")})
+			store.mu.Unlock()
+		}
+	}))
+
+	err := NewAssignmentOp(client).Assign(context.Background(), LicenseID(id), "hsm-1", "client-1")
+	assert.ErrorIs(err, ErrLicenseAssigned)
+	assert.ErrorContains(err, "reassigned concurrently")
+}
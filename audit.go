@@ -0,0 +1,297 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/sacloud/saclient-go"
+)
+
+// AuditOutcome 変更操作の結果
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+)
+
+// AuditEntry 変更操作1回分の監査記録
+type AuditEntry struct {
+	// Seq AuditLog内での通し番号。1から始まる
+	Seq uint64 `json:"seq"`
+
+	// Time 操作が終わった時刻
+	Time time.Time `json:"time"`
+
+	// Actor 操作者。saclientのプロファイル名かWithAuditActorで指定した名前
+	Actor string `json:"actor"`
+
+	Zone      string           `json:"zone,omitempty"`
+	Method    string           `json:"method"`
+	Operation v1.OperationName `json:"operation"`
+
+	// 操作対象のリソースID。Createの場合は作成されたリソースのID
+	CloudHSMID string `json:"cloudhsm_id,omitempty"`
+	ClientID   string `json:"client_id,omitempty"`
+	PeerID     string `json:"peer_id,omitempty"`
+	LicenseID  string `json:"license_id,omitempty"`
+
+	// Before 操作前のリソース。Createの場合や取得できなかった場合は空
+	Before json.RawMessage `json:"before,omitempty"`
+
+	// After 操作後のリソース。Deleteの場合や失敗した場合は空
+	After json.RawMessage `json:"after,omitempty"`
+
+	Outcome    AuditOutcome `json:"outcome"`
+	StatusCode int          `json:"status,omitempty"`
+	Error      string       `json:"error,omitempty"`
+
+	// PrevHash 直前の記録のHash。最初の記録では空
+	PrevHash string `json:"prev_hash"`
+
+	// Hash Hashを空にした記録(PrevHashを含む)のJSONから求めたSHA-256
+	Hash string `json:"hash"`
+}
+
+// AuditHook 各OpのCreate/Update/Deleteのたびに呼ばれる
+//
+// Auditが返したエラーは操作の結果には影響せず、WithLoggerの指定があればそこに記録される。
+type AuditHook interface {
+	Audit(ctx context.Context, e *AuditEntry) error
+}
+
+// WithAuditHook 変更操作をAuditHookに記録する
+func WithAuditHook(h AuditHook) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.audit = h
+	}
+}
+
+// WithAuditActor 監査記録の操作者を指定する。指定しない場合はsaclientのプロファイル名を用いる
+func WithAuditActor(actor string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.actor = func() string { return actor }
+	}
+}
+
+// profileActor saclientのプロファイル名。初めて必要になった時点で解決する
+func profileActor(client saclient.ClientAPI) func() string {
+	return sync.OnceValue(func() string {
+		if p, err := client.Profile(); err == nil && p != nil {
+			return p.Name
+		}
+		return ""
+	})
+}
+
+// mutate 変更操作をs.invokeで呼び出し、AuditHookがあれば前後の状態とともに記録する
+//
+// beforeは操作前、afterは操作が成功した後に呼ばれ、それぞれのリソースを返す。
+func mutate[T any](ctx context.Context, s *settings, c *Call, before func(context.Context) (any, error), f func(context.Context) (T, error), after func(*Call, T) any) (T, error) {
+	var ret T
+	err := s.mutate(ctx, c, before, func(ctx context.Context) (err error) {
+		ret, err = f(ctx)
+		return err
+	}, func() any {
+		return after(c, ret)
+	})
+	return ret, err
+}
+
+func (s *settings) mutate(ctx context.Context, c *Call, before func(context.Context) (any, error), f func(context.Context) error, after func() any) error {
+	// WithDryRunの場合は何も変更しないので記録しない
+	if s.audit == nil || s.dryRun {
+		return s.invoke(ctx, c, f)
+	}
+
+	var e AuditEntry
+	if before != nil {
+		if v, err := before(ctx); err == nil {
+			e.Before = snapshotOf(v)
+		}
+	}
+
+	err := s.invoke(ctx, c, f)
+	if err == nil {
+		e.Outcome = AuditSuccess
+		if after != nil {
+			e.After = snapshotOf(after())
+		}
+	} else {
+		e.Outcome = AuditFailure
+		e.Error = Redact(err.Error())
+	}
+
+	e.Time = time.Now().UTC()
+	if s.actor != nil {
+		e.Actor = s.actor()
+	}
+	e.Zone = c.Zone
+	e.Method = c.Method
+	e.Operation = c.Operation
+	e.CloudHSMID = c.CloudHSMID
+	e.ClientID = c.ClientID
+	e.PeerID = c.PeerID
+	e.LicenseID = c.LicenseID
+	e.StatusCode = c.StatusCode
+
+	if aerr := s.audit.Audit(ctx, &e); aerr != nil && s.logger != nil {
+		s.logger.ErrorContext(ctx, "cloudhsm: audit failed", slog.Any("call", c), slog.String("error", aerr.Error()))
+	}
+	return err
+}
+
+// snapshotOf 監査記録に残すリソースの表現。nilなら空
+//
+// LocalRouterのSecretKeyはJSONに書き出されないので、監査記録にも残らない。
+func snapshotOf(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	j, err := json.Marshal(v)
+	if err != nil || bytes.Equal(j, []byte("null")) {
+		return nil
+	}
+	return j
+}
+
+// AuditLog 監査記録をハッシュチェーンで繋いだJSON Linesとして書き出すAuditHook
+//
+// 各記録のHashは直前の記録のHashを含めて計算するため、途中の記録を改竄・削除すると
+// VerifyAuditLogで検出できる。
+type AuditLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	seq    uint64
+	last   string
+}
+
+var _ AuditHook = (*AuditLog)(nil)
+
+// NewAuditLog io.Writerに書き出すAuditLogを作成する
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// OpenAuditLog JSON Linesファイルに追記するAuditLogを作成する
+//
+// ファイルに記録が既にあれば、その最後の記録からチェーンを続ける。
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600) //nolint:gosec // the path is chosen by the caller
+	if err != nil {
+		return nil, NewError("OpenAuditLog", err)
+	}
+
+	l := &AuditLog{w: f, closer: f}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			_ = f.Close()
+			return nil, NewError("OpenAuditLog", err)
+		}
+		l.seq, l.last = e.Seq, e.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		_ = f.Close()
+		return nil, NewError("OpenAuditLog", err)
+	}
+	return l, nil
+}
+
+// Audit 記録に通し番号とハッシュを付けて書き出す
+func (l *AuditLog) Audit(_ context.Context, e *AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = l.seq + 1
+	e.PrevHash = l.last
+	h, err := e.hash()
+	if err != nil {
+		return err
+	}
+	e.Hash = h
+
+	j, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.w.Write(append(j, '\n')); err != nil {
+		return err
+	}
+	l.seq, l.last = e.Seq, e.Hash
+	return nil
+}
+
+// Close OpenAuditLogで開いたファイルを閉じる
+func (l *AuditLog) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+func (e *AuditEntry) hash() (string, error) {
+	tmp := *e
+	tmp.Hash = ""
+	j, err := json.Marshal(&tmp)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(j)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyAuditLog AuditLogが書き出したJSON Linesのハッシュチェーンを検証し、検証した記録の数を返す
+func VerifyAuditLog(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+
+	var n int
+	var last string
+	for scanner.Scan() {
+		n++
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return n - 1, NewError("VerifyAuditLog", fmt.Errorf("line %d: %w", n, err))
+		} else if e.PrevHash != last {
+			return n - 1, NewError("VerifyAuditLog", fmt.Errorf("line %d: chain broken", n))
+		} else if h, err := e.hash(); err != nil {
+			return n - 1, NewError("VerifyAuditLog", fmt.Errorf("line %d: %w", n, err))
+		} else if h != e.Hash {
+			return n - 1, NewError("VerifyAuditLog", fmt.Errorf("line %d: hash mismatch", n))
+		}
+		last = e.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return n, NewError("VerifyAuditLog", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditedLicenseClient serves a license that is renamed by PUT and
+// rejects DELETE with 403.
+func newAuditedLicenseClient(t *testing.T, log AuditHook) *v1.Client {
+	return newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lic := TemplateLicense
+		lic.SetID("license-1")
+		switch r.Method {
+		case http.MethodGet:
+			lic.SetName("before")
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(lic)})
+		case http.MethodPut:
+			lic.SetName("after")
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(lic)})
+		default:
+			respondJSON(w, http.StatusForbidden, newErrorResponse("forbidden"))
+		}
+	}), WithAuditHook(log), WithAuditActor("alice"))
+}
+
+func auditEntries(t *testing.T, buf []byte) []AuditEntry {
+	var ret []AuditEntry
+	for _, line := range bytes.Split(bytes.TrimSpace(buf), []byte("\n")) {
+		var e AuditEntry
+		require.NoError(t, json.Unmarshal(line, &e))
+		ret = append(ret, e)
+	}
+	return ret
+}
+
+func TestAudit_UpdateAndDelete(t *testing.T) {
+	assert := require.New(t)
+	var buf bytes.Buffer
+	client := newAuditedLicenseClient(t, NewAuditLog(&buf))
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	_, err := api.Update(ctx, "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "after"})
+	assert.NoError(err)
+	err = api.Delete(ctx, "license-1")
+	assert.Error(err)
+
+	entries := auditEntries(t, buf.Bytes())
+	assert.Len(entries, 2)
+
+	update := entries[0]
+	assert.Equal(uint64(1), update.Seq)
+	assert.Equal("alice", update.Actor)
+	assert.Equal("License.Update", update.Method)
+	assert.Equal("license-1", update.LicenseID)
+	assert.Equal(AuditSuccess, update.Outcome)
+	assert.Contains(string(update.Before), `"Name":"before"`)
+	assert.Contains(string(update.After), `"Name":"after"`)
+	assert.Empty(update.PrevHash)
+	assert.False(update.Time.IsZero())
+
+	del := entries[1]
+	assert.Equal(AuditFailure, del.Outcome)
+	assert.Equal(http.StatusForbidden, del.StatusCode)
+	assert.NotEmpty(del.Error)
+	assert.NotEmpty(del.Before)
+	assert.Empty(del.After)
+	assert.Equal(update.Hash, del.PrevHash)
+
+	n, err := VerifyAuditLog(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(2, n)
+}
+
+func TestAudit_BeforeBypassesInterceptors(t *testing.T) {
+	assert := require.New(t)
+	var buf bytes.Buffer
+	var methods []string
+	record := WithInterceptor(func(ctx context.Context, c *Call, next func(context.Context) error) error {
+		methods = append(methods, c.Method)
+		return next(ctx)
+	})
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lic := TemplateLicense
+		lic.SetID("license-1")
+		respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(lic)})
+	}), WithAuditHook(NewAuditLog(&buf)), record)
+
+	_, err := NewLicenseOp(client).Update(context.Background(), "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "after"})
+	assert.NoError(err)
+
+	// the snapshot read must not show up as a separate call
+	assert.Equal([]string{"License.Update"}, methods)
+	assert.NotEmpty(auditEntries(t, buf.Bytes())[0].Before)
+}
+
+func TestAudit_Tampered(t *testing.T) {
+	assert := require.New(t)
+	var buf bytes.Buffer
+	client := newAuditedLicenseClient(t, NewAuditLog(&buf))
+	api := NewLicenseOp(client)
+
+	for range 3 {
+		_, err := api.Update(context.Background(), "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "after"})
+		assert.NoError(err)
+	}
+
+	tampered := strings.Replace(buf.String(), `"actor":"alice"`, `"actor":"mallory"`, 1)
+	n, err := VerifyAuditLog(strings.NewReader(tampered))
+	assert.ErrorContains(err, "line 1: hash mismatch")
+	assert.Equal(0, n)
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	dropped := lines[0] + lines[2]
+	n, err = VerifyAuditLog(strings.NewReader(dropped))
+	assert.ErrorContains(err, "line 2: chain broken")
+	assert.Equal(1, n)
+}
+
+func TestAudit_PeerCreate(t *testing.T) {
+	assert := require.New(t)
+	var buf bytes.Buffer
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			respondJSON(w, http.StatusNoContent, nil)
+			return
+		}
+		respondJSON(w, http.StatusOK, v1.CloudHSMPeerList{Peers: []v1.CloudHSMPeer{
+			{ID: "router-1", Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusUP), Routes: []string{}},
+		}})
+	}), WithAuditHook(NewAuditLog(&buf)))
+
+	api, err := NewPeerOp(client, &TemplatePartition)
+	assert.NoError(err)
+	err = api.Create(context.Background(), CloudHSMPeerCreateParams{RouterID: "router-1", SecretKey: "pairing-secret"})
+	assert.NoError(err)
+
+	assert.NotContains(buf.String(), "pairing-secret")
+	entries := auditEntries(t, buf.Bytes())
+	assert.Len(entries, 1)
+	assert.Equal("router-1", entries[0].PeerID)
+	assert.Equal(TemplateCloudHSM.ID, entries[0].CloudHSMID)
+	assert.Empty(entries[0].Before)
+	assert.Contains(string(entries[0].After), `"Status":"UP"`)
+}
+
+func TestAudit_PartitionSecretKey(t *testing.T) {
+	assert := require.New(t)
+	var buf bytes.Buffer
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hsm := TemplateCloudHSM
+		hsm.SetID("hsm-1")
+		hsm.SetLocalRouter(v1.NewNilCloudHSMLocalRouter(v1.CloudHSMLocalRouter{
+			ResourceID: v1.NewOptString("router-1"),
+			SecretKey:  v1.NewOptString("TOPSECRET"),
+		}))
+		respondJSON(w, http.StatusOK, v1.WrappedCloudHSM{CloudHSM: hsm})
+	}), WithAuditHook(NewAuditLog(&buf)))
+
+	_, err := NewCloudHSMOp(client).Update(context.Background(), "hsm-1", CloudHSMUpdateParams{Name: "renamed"})
+	assert.NoError(err)
+
+	// the log is append-only, so the key must never be written to it
+	assert.NotContains(buf.String(), "TOPSECRET")
+	entries := auditEntries(t, buf.Bytes())
+	assert.Len(entries, 1)
+	assert.Contains(string(entries[0].Before), `"LocalRouter":{"ResourceID":"router-1"}`)
+	assert.NotContains(string(entries[0].After), "SecretKey")
+}
+
+func TestOpenAuditLog_Resume(t *testing.T) {
+	assert := require.New(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	for range 2 {
+		log, err := OpenAuditLog(path)
+		assert.NoError(err)
+		client := newAuditedLicenseClient(t, log)
+		_, err = NewLicenseOp(client).Update(context.Background(), "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "after"})
+		assert.NoError(err)
+		assert.NoError(log.Close())
+	}
+
+	f, err := os.Open(path)
+	assert.NoError(err)
+	defer f.Close() //nolint:errcheck
+
+	n, err := VerifyAuditLog(f)
+	assert.NoError(err)
+	assert.Equal(2, n)
+}
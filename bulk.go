@@ -0,0 +1,197 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// BulkFailureMode 一括操作の途中で失敗したときの扱い
+type BulkFailureMode int
+
+const (
+	// BulkContinue 失敗しても残りを続ける
+	BulkContinue BulkFailureMode = iota
+
+	// BulkStop 失敗したら残りを始めない。実行中のものは終わるまで待つ
+	BulkStop
+
+	// BulkRollback BulkStopに加えて、作成済みのものを削除する。削除の一括操作ではBulkStopと同じ
+	BulkRollback
+)
+
+// BulkOptions 一括操作の動作を指定する
+//
+// 各操作は通常のCreate/Deleteと同じくWithRateLimiterやWithRetryPolicyの指定に従う。
+type BulkOptions struct {
+	// Concurrency 同時に実行する数。0なら4
+	Concurrency int
+
+	// OnFailure 失敗したときの扱い
+	OnFailure BulkFailureMode
+}
+
+// BulkItemResult 一括操作の1件分の結果。Iはリソースの種類ごとのID型
+type BulkItemResult[T any, I ~string] struct {
+	// Index 引数の中での位置
+	Index int
+
+	// ID 作成または削除したリソースのID
+	ID I
+
+	// Value 作成したリソース。LicenseOp.DeleteByTagでは削除したリソース。
+	// ClientOp.DeleteManyの場合や、Errがある場合、実行しなかった場合はnil
+	Value *T
+
+	Err error
+
+	// Skipped 先に失敗したものがあったか、ctxが終わったために実行しなかった
+	Skipped bool
+
+	// RolledBack BulkRollbackによって削除された
+	RolledBack bool
+
+	// RollbackErr BulkRollbackによる削除に失敗した理由
+	RollbackErr error
+}
+
+// BulkResult 一括操作の結果。Itemsは引数と同じ順に並ぶ
+type BulkResult[T any, I ~string] struct {
+	Items []BulkItemResult[T, I]
+}
+
+// Err 失敗したものがあれば*BulkErrorを返す
+func (r *BulkResult[T, I]) Err() error {
+	var errs []error
+	for _, item := range r.Items {
+		if item.Err != nil {
+			errs = append(errs, item.Err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BulkError{Total: len(r.Items), Errs: errs}
+}
+
+// BulkError 一括操作のうち失敗したもの
+type BulkError struct {
+	Total int
+	Errs  []error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d items failed: %s", len(e.Errs), e.Total, e.Errs[0].Error())
+}
+
+func (e *BulkError) Unwrap() []error {
+	return e.Errs
+}
+
+// runBulk n件の処理fをopts.Concurrencyまで並行して実行する。fはi番目の結果を書き込む
+func runBulk[T any, I ~string](ctx context.Context, n int, opts BulkOptions, f func(ctx context.Context, item *BulkItemResult[T, I])) *BulkResult[T, I] {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	res := &BulkResult[T, I]{Items: make([]BulkItemResult[T, I], n)}
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i := range res.Items {
+		item := &res.Items[i]
+		item.Index = i
+
+		acquired := false
+		select {
+		case sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil || (opts.OnFailure != BulkContinue && failed.Load()) {
+			if acquired {
+				<-sem
+			}
+			item.Skipped = true
+			if ctx.Err() != nil {
+				item.Err = ctx.Err()
+			}
+			continue
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+			f(ctx, item)
+			if item.Err != nil {
+				failed.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+	return res
+}
+
+// rollback 失敗したものがあれば、作成済みのものをdeleteで削除する
+//
+// ctxが終わっていても削除は行う。
+func rollback[T any, I ~string](ctx context.Context, res *BulkResult[T, I], opts BulkOptions, del func(ctx context.Context, id I) error) {
+	if res.Err() == nil {
+		return
+	}
+	runBulk(context.WithoutCancel(ctx), len(res.Items), BulkOptions{Concurrency: opts.Concurrency}, func(ctx context.Context, r *BulkItemResult[struct{}, I]) {
+		item := &res.Items[r.Index]
+		if item.Value == nil {
+			return
+		}
+		if item.RollbackErr = del(ctx, item.ID); item.RollbackErr == nil {
+			item.RolledBack = true
+		}
+	})
+}
+
+// CreateMany クライアントを並行して作成する
+//
+// 戻り値のエラーは、失敗したものがあればBulkResult.Errと同じ*BulkErrorになる。
+func (op *ClientOp) CreateMany(ctx context.Context, params []CloudHSMClientCreateParams, opts BulkOptions) (*BulkResult[Client, ClientID], error) {
+	res := runBulk(ctx, len(params), opts, func(ctx context.Context, item *BulkItemResult[Client, ClientID]) {
+		item.Value, item.Err = op.Create(ctx, params[item.Index])
+		if item.Value != nil {
+			item.ID = item.Value.ID
+		}
+	})
+
+	if opts.OnFailure == BulkRollback {
+		rollback(ctx, res, opts, func(ctx context.Context, id ClientID) error {
+			return op.Delete(ctx, id)
+		})
+	}
+	return res, res.Err()
+}
+
+// DeleteMany クライアントを並行して削除する
+func (op *ClientOp) DeleteMany(ctx context.Context, ids []ClientID, opts BulkOptions) (*BulkResult[Client, ClientID], error) {
+	res := runBulk(ctx, len(ids), opts, func(ctx context.Context, item *BulkItemResult[Client, ClientID]) {
+		item.Err = op.Delete(ctx, ids[item.Index])
+	})
+	for i := range res.Items {
+		res.Items[i].ID = ids[i]
+	}
+	return res, res.Err()
+}
@@ -0,0 +1,158 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"sync"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkServer creates clients named anything but "bad" and deletes any client
+// except "missing".
+type bulkServer struct {
+	mu       sync.Mutex
+	created  []string
+	deleted  []string
+	inFlight int
+	maxSeen  int
+}
+
+func (s *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxSeen = max(s.maxSeen, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	switch r.Method {
+	case http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		var req v1.WrappedCreateCloudHSMClient
+		if err := json.Unmarshal(body, &req); err != nil || req.Client.Name == "bad" {
+			respondJSON(w, http.StatusUnprocessableEntity, newErrorResponse("bad request"))
+			return
+		}
+		req.Client.ID = "id-" + req.Client.Name
+		s.mu.Lock()
+		s.created = append(s.created, req.Client.ID)
+		s.mu.Unlock()
+		respondJSON(w, http.StatusCreated, &req)
+	case http.MethodDelete:
+		id := path.Base(r.URL.Path)
+		if id == "missing" {
+			respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+			return
+		}
+		s.mu.Lock()
+		s.deleted = append(s.deleted, id)
+		s.mu.Unlock()
+		respondJSON(w, http.StatusNoContent, nil)
+	default:
+		respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+	}
+}
+
+func newBulkClientOp(t *testing.T, srv *bulkServer) ClientAPI {
+	hsm := TemplatePartition
+	hsm.ID = "hsm-1"
+	api, err := NewClientOp(newTestClientWithHandler(t, srv), &hsm)
+	require.NoError(t, err)
+	return api
+}
+
+func bulkParams(names ...string) []CloudHSMClientCreateParams {
+	var ret []CloudHSMClientCreateParams
+	for _, n := range names {
+		ret = append(ret, CloudHSMClientCreateParams{Name: n, Certificate: "cert"})
+	}
+	return ret
+}
+
+func TestClientOp_CreateMany(t *testing.T) {
+	assert := require.New(t)
+	srv := &bulkServer{}
+	api := newBulkClientOp(t, srv)
+
+	res, err := api.CreateMany(context.Background(), bulkParams("a", "bad", "c", "d"), BulkOptions{Concurrency: 2})
+	var berr *BulkError
+	assert.True(errors.As(err, &berr))
+	assert.Len(berr.Errs, 1)
+	assert.Equal(4, berr.Total)
+
+	assert.Len(res.Items, 4)
+	for i, item := range res.Items {
+		assert.Equal(i, item.Index)
+		assert.False(item.Skipped)
+	}
+	assert.Equal(ClientID("id-a"), res.Items[0].ID)
+	assert.Equal("a", res.Items[0].Value.Name)
+	assert.Error(res.Items[1].Err)
+	assert.Nil(res.Items[1].Value)
+	assert.Equal(ClientID("id-d"), res.Items[3].ID)
+	assert.Len(srv.created, 3)
+	assert.LessOrEqual(srv.maxSeen, 2)
+}
+
+func TestClientOp_CreateMany_Rollback(t *testing.T) {
+	assert := require.New(t)
+	srv := &bulkServer{}
+	api := newBulkClientOp(t, srv)
+
+	res, err := api.CreateMany(context.Background(), bulkParams("a", "b", "bad", "d"), BulkOptions{Concurrency: 1, OnFailure: BulkRollback})
+	assert.Error(err)
+
+	assert.True(res.Items[0].RolledBack)
+	assert.True(res.Items[1].RolledBack)
+	assert.Error(res.Items[2].Err)
+	assert.True(res.Items[3].Skipped)
+	assert.NoError(res.Items[3].Err)
+
+	slices.Sort(srv.deleted)
+	assert.Equal([]string{"id-a", "id-b"}, srv.created)
+	assert.Equal([]string{"id-a", "id-b"}, srv.deleted)
+}
+
+func TestClientOp_DeleteMany(t *testing.T) {
+	assert := require.New(t)
+	srv := &bulkServer{}
+	api := newBulkClientOp(t, srv)
+
+	res, err := api.DeleteMany(context.Background(), []ClientID{"x", "missing", "y"}, BulkOptions{})
+	assert.Error(err)
+	assert.Equal([]ClientID{"x", "missing", "y"}, []ClientID{res.Items[0].ID, res.Items[1].ID, res.Items[2].ID})
+	assert.NoError(res.Items[0].Err)
+	assert.Error(res.Items[1].Err)
+	assert.NoError(res.Items[2].Err)
+	assert.ElementsMatch([]string{"x", "y"}, srv.deleted)
+
+	res, err = api.DeleteMany(context.Background(), []ClientID{"z"}, BulkOptions{})
+	assert.NoError(err)
+	assert.NoError(res.Err())
+}
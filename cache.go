@@ -0,0 +1,375 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheOptions Cacheの保持期間
+type CacheOptions struct {
+	// TTL Readの結果を保持する期間。0なら30秒
+	TTL time.Duration
+
+	// ListTTL Listの結果を保持する期間。0ならTTLと同じ
+	ListTTL time.Duration
+
+	// FetchTimeout 同時の呼び出しで共有する取得1回に許す時間。0なら1分
+	//
+	// 共有する取得は呼び出し側のキャンセルに左右されないため、応答のない取得が
+	// 後続の呼び出しを待たせ続けないよう、これで打ち切る。
+	FetchTimeout time.Duration
+}
+
+// Cache List/Readの結果を保持する読み取りキャッシュ
+//
+// WithCacheで指定すると、そのクライアントから作成した各OpのList/Readは保持している結果を返し、
+// 同時に行われた同じ呼び出しは1回にまとめられる。同じクライアントを通じたCreate/Update/Deleteは
+// 成否にかかわらず関係する結果を破棄する。他のプロセスによる変更はTTLが過ぎるまで反映されない。
+type Cache struct {
+	opts  CacheOptions
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	gen     uint64
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// NewCache Cacheを作成する
+func NewCache(opts CacheOptions) *Cache {
+	if opts.TTL <= 0 {
+		opts.TTL = 30 * time.Second
+	}
+	if opts.ListTTL <= 0 {
+		opts.ListTTL = opts.TTL
+	}
+	if opts.FetchTimeout <= 0 {
+		opts.FetchTimeout = time.Minute
+	}
+	return &Cache{opts: opts, entries: map[string]cacheEntry{}}
+}
+
+// WithCache 各OpのList/ReadにCacheを用いる。複数のクライアントで同じCacheを共有してはならない
+func WithCache(c *Cache) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.cache = c
+	}
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache Cacheを用いずにAPIから取得するcontextを返す。取得した結果は保持される
+//
+// Snapshot、CheckDrift、Watcherは常に最新の状態を取得するためにこれを用いる。
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// Purge 保持しているすべての結果を破棄する
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	clear(c.entries)
+}
+
+// invalidate keysの結果を破棄する。":"で終わるキーはそれで始まるすべてのキーを表す
+func (c *Cache) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 実行中の取得が破棄より前の結果を書き込まないよう世代を進める
+	c.gen++
+	for k := range c.entries {
+		for _, key := range keys {
+			if k == key || (strings.HasSuffix(key, ":") && strings.HasPrefix(k, key)) {
+				delete(c.entries, k)
+				break
+			}
+		}
+	}
+}
+
+func (c *Cache) lookup(key string) (any, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && time.Now().After(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	return e.value, c.gen, ok
+}
+
+func (c *Cache) store(key string, gen uint64, v any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen == c.gen {
+		c.entries[key] = cacheEntry{value: v, expires: time.Now().Add(ttl)}
+	}
+}
+
+// cached keyの結果があればそれを、なければfの結果を保持して返す
+func cached[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, f func(context.Context) (T, error)) (T, error) {
+	v, gen, ok := c.lookup(key)
+	if ok && ctx.Value(bypassCacheKey{}) == nil {
+		return v.(T), nil
+	}
+
+	// 取得は同じ呼び出しに加わったすべての呼び出し側が共有するため、最初の呼び出し側の
+	// キャンセルや期限に左右されないようにし、代わりにFetchTimeoutで打ち切る。
+	// 各呼び出し側は自身のctxが終われば待つのをやめる
+	ch := c.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.FetchTimeout)
+		defer cancel()
+		v, err := f(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, gen, v, ttl)
+		return v, nil
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case r := <-ch:
+		if r.Err != nil {
+			return zero, r.Err
+		}
+		return r.Val.(T), nil
+	}
+}
+
+// cloneValue 呼び出し側の変更が保持している結果に及ばないよう、fで複製する
+func cloneValue[T any](p *T, f func(T) T) *T {
+	if p == nil {
+		return nil
+	}
+	v := f(*p)
+	return &v
+}
+
+// cloneList 呼び出し側の変更が保持している結果に及ばないよう、要素ごとにfで複製する
+func cloneList[T any](list []T, f func(T) T) []T {
+	if list == nil {
+		return nil
+	}
+	ret := make([]T, len(list))
+	for i := range list {
+		ret[i] = f(list[i])
+	}
+	return ret
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func clonePartition(p Partition) Partition {
+	p.Description = clonePtr(p.Description)
+	p.Tags = slices.Clone(p.Tags)
+	p.LocalRouter = clonePtr(p.LocalRouter)
+	return p
+}
+
+// cloneClient Clientは参照を含まないので値の複製で足りる
+func cloneClient(c Client) Client {
+	return c
+}
+
+func clonePeer(p Peer) Peer {
+	p.Index = clonePtr(p.Index)
+	p.Routes = slices.Clone(p.Routes)
+	return p
+}
+
+func cloneLicense(l License) License {
+	l.Description = clonePtr(l.Description)
+	l.Tags = slices.Clone(l.Tags)
+	return l
+}
+
+type cachedCloudHSMOp struct {
+	next  CloudHSMAPI
+	cache *Cache
+}
+
+var _ CloudHSMAPI = (*cachedCloudHSMOp)(nil)
+
+func (op *cachedCloudHSMOp) List(ctx context.Context) ([]Partition, error) {
+	ret, err := cached(ctx, op.cache, "cloudhsm:list", op.cache.opts.ListTTL, op.next.List)
+	return cloneList(ret, clonePartition), err
+}
+
+func (op *cachedCloudHSMOp) Create(ctx context.Context, request CloudHSMCreateParams) (*Partition, error) {
+	defer op.cache.invalidate("cloudhsm:list")
+	return op.next.Create(ctx, request)
+}
+
+func (op *cachedCloudHSMOp) Read(ctx context.Context, id PartitionID) (*Partition, error) {
+	ret, err := cached(ctx, op.cache, "cloudhsm:"+string(id), op.cache.opts.TTL, func(ctx context.Context) (*Partition, error) {
+		return op.next.Read(ctx, id)
+	})
+	return cloneValue(ret, clonePartition), err
+}
+
+func (op *cachedCloudHSMOp) Update(ctx context.Context, id PartitionID, params CloudHSMUpdateParams) (*Partition, error) {
+	defer op.cache.invalidate("cloudhsm:list", "cloudhsm:"+string(id))
+	return op.next.Update(ctx, id, params)
+}
+
+func (op *cachedCloudHSMOp) Delete(ctx context.Context, id PartitionID, opts ...DeleteOption) error {
+	defer op.cache.invalidate("cloudhsm:list", "cloudhsm:"+string(id), "client:"+string(id)+":", "peer:"+string(id)+":")
+	// 削除保護の判定には最新の状態を用いる
+	return op.next.Delete(BypassCache(ctx), id, opts...)
+}
+
+func (op *cachedCloudHSMOp) DeleteCascade(ctx context.Context, id PartitionID, opts DeleteCascadeOptions) error {
+	defer op.cache.invalidate("cloudhsm:list", "cloudhsm:"+string(id), "client:"+string(id)+":", "peer:"+string(id)+":")
+	return op.next.DeleteCascade(BypassCache(ctx), id, opts)
+}
+
+type cachedClientOp struct {
+	next   ClientAPI
+	cache  *Cache
+	prefix string
+}
+
+var _ ClientAPI = (*cachedClientOp)(nil)
+
+func (op *cachedClientOp) List(ctx context.Context) ([]Client, error) {
+	ret, err := cached(ctx, op.cache, op.prefix+"list", op.cache.opts.ListTTL, op.next.List)
+	return cloneList(ret, cloneClient), err
+}
+
+func (op *cachedClientOp) Create(ctx context.Context, request CloudHSMClientCreateParams) (*Client, error) {
+	defer op.cache.invalidate(op.prefix + "list")
+	return op.next.Create(ctx, request)
+}
+
+func (op *cachedClientOp) Read(ctx context.Context, id ClientID) (*Client, error) {
+	ret, err := cached(ctx, op.cache, op.prefix+string(id), op.cache.opts.TTL, func(ctx context.Context) (*Client, error) {
+		return op.next.Read(ctx, id)
+	})
+	return cloneValue(ret, cloneClient), err
+}
+
+func (op *cachedClientOp) Update(ctx context.Context, id ClientID, params CloudHSMClientUpdateParams) (*Client, error) {
+	defer op.cache.invalidate(op.prefix+"list", op.prefix+string(id))
+	return op.next.Update(ctx, id, params)
+}
+
+func (op *cachedClientOp) Delete(ctx context.Context, id ClientID) error {
+	defer op.cache.invalidate(op.prefix+"list", op.prefix+string(id))
+	return op.next.Delete(ctx, id)
+}
+
+func (op *cachedClientOp) CreateMany(ctx context.Context, params []CloudHSMClientCreateParams, opts BulkOptions) (*BulkResult[Client, ClientID], error) {
+	defer op.cache.invalidate(op.prefix)
+	return op.next.CreateMany(ctx, params, opts)
+}
+
+func (op *cachedClientOp) DeleteMany(ctx context.Context, ids []ClientID, opts BulkOptions) (*BulkResult[Client, ClientID], error) {
+	defer op.cache.invalidate(op.prefix)
+	return op.next.DeleteMany(ctx, ids, opts)
+}
+
+type cachedPeerOp struct {
+	next   PeerAPI
+	cache  *Cache
+	prefix string
+}
+
+var _ PeerAPI = (*cachedPeerOp)(nil)
+
+func (op *cachedPeerOp) List(ctx context.Context) ([]Peer, error) {
+	ret, err := cached(ctx, op.cache, op.prefix+"list", op.cache.opts.ListTTL, op.next.List)
+	return cloneList(ret, clonePeer), err
+}
+
+func (op *cachedPeerOp) Create(ctx context.Context, request CloudHSMPeerCreateParams) error {
+	defer op.cache.invalidate(op.prefix + "list")
+	return op.next.Create(ctx, request)
+}
+
+func (op *cachedPeerOp) Delete(ctx context.Context, id RouterID) error {
+	defer op.cache.invalidate(op.prefix + "list")
+	return op.next.Delete(ctx, id)
+}
+
+type cachedLicenseOp struct {
+	next  LicenseAPI
+	cache *Cache
+}
+
+var _ LicenseAPI = (*cachedLicenseOp)(nil)
+
+func (op *cachedLicenseOp) List(ctx context.Context) ([]License, error) {
+	ret, err := cached(ctx, op.cache, "license:list", op.cache.opts.ListTTL, op.next.List)
+	return cloneList(ret, cloneLicense), err
+}
+
+func (op *cachedLicenseOp) Create(ctx context.Context, request CloudHSMSoftwareLicenseCreateParams) (*License, error) {
+	defer op.cache.invalidate("license:list")
+	return op.next.Create(ctx, request)
+}
+
+func (op *cachedLicenseOp) Read(ctx context.Context, id LicenseID) (*License, error) {
+	ret, err := cached(ctx, op.cache, "license:"+string(id), op.cache.opts.TTL, func(ctx context.Context) (*License, error) {
+		return op.next.Read(ctx, id)
+	})
+	return cloneValue(ret, cloneLicense), err
+}
+
+func (op *cachedLicenseOp) Update(ctx context.Context, id LicenseID, params CloudHSMSoftwareLicenseUpdateParams) (*License, error) {
+	defer op.cache.invalidate("license:list", "license:"+string(id))
+	return op.next.Update(ctx, id, params)
+}
+
+func (op *cachedLicenseOp) Delete(ctx context.Context, id LicenseID, opts ...DeleteOption) error {
+	defer op.cache.invalidate("license:list", "license:"+string(id))
+	// 削除保護の判定には最新の状態を用いる
+	return op.next.Delete(BypassCache(ctx), id, opts...)
+}
+
+func (op *cachedLicenseOp) CreateBatch(ctx context.Context, p LicenseBatchParams, opts BulkOptions) (*BulkResult[License, LicenseID], error) {
+	defer op.cache.invalidate("license:")
+	return op.next.CreateBatch(ctx, p, opts)
+}
+
+func (op *cachedLicenseOp) DeleteByTag(ctx context.Context, tag string, opts BulkOptions, dopts ...DeleteOption) (*BulkResult[License, LicenseID], error) {
+	defer op.cache.invalidate("license:")
+	return op.next.DeleteByTag(ctx, tag, opts, dopts...)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingLicenseClient serves license-1 and counts requests per method.
+func newCountingLicenseClient(t *testing.T, cache *Cache, delay time.Duration) (*v1.Client, map[string]*atomic.Int32) {
+	counts := map[string]*atomic.Int32{
+		http.MethodGet: {}, http.MethodPost: {}, http.MethodPut: {}, http.MethodDelete: {},
+	}
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counts[r.Method].Add(1)
+		time.Sleep(delay)
+
+		lic := TemplateLicense
+		lic.SetID("license-1")
+		lic.SetTags([]string{"tag1"})
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/licenses"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMSoftwareLicenseList{
+				Count: 1, From: v1.NewOptInt(0), Total: v1.NewOptInt(1), Licenses: []v1.CloudHSMSoftwareLicense{lic},
+			})
+		case r.Method == http.MethodGet, r.Method == http.MethodPut:
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(lic)})
+		default:
+			respondJSON(w, http.StatusForbidden, newErrorResponse("forbidden"))
+		}
+	}), WithCache(cache))
+	return client, counts
+}
+
+func TestCache_ReadAndInvalidate(t *testing.T) {
+	assert := require.New(t)
+	client, counts := newCountingLicenseClient(t, NewCache(CacheOptions{TTL: time.Hour}), 0)
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	first, err := api.Read(ctx, "license-1")
+	assert.NoError(err)
+	first.Name = "changed by caller"
+	second, err := api.Read(ctx, "license-1")
+	assert.NoError(err)
+	assert.NotEqual("changed by caller", second.Name)
+	_, err = api.List(ctx)
+	assert.NoError(err)
+	_, err = NewLicenseOp(client).List(ctx)
+	assert.NoError(err)
+	assert.Equal(int32(2), counts[http.MethodGet].Load())
+
+	_, err = api.Update(ctx, "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "x"})
+	assert.NoError(err)
+	_, err = api.Read(ctx, "license-1")
+	assert.NoError(err)
+	_, err = api.List(ctx)
+	assert.NoError(err)
+	assert.Equal(int32(4), counts[http.MethodGet].Load())
+
+	// Failed mutations invalidate too, since they may have been applied.
+	assert.Error(api.Delete(ctx, "license-1"))
+	_, err = api.Read(ctx, "license-1")
+	assert.NoError(err)
+	assert.Equal(int32(5), counts[http.MethodGet].Load())
+
+	_, err = api.Read(BypassCache(ctx), "license-1")
+	assert.NoError(err)
+	assert.Equal(int32(6), counts[http.MethodGet].Load())
+}
+
+func TestCache_TTL(t *testing.T) {
+	assert := require.New(t)
+	cache := NewCache(CacheOptions{TTL: time.Hour, ListTTL: time.Millisecond})
+	client, counts := newCountingLicenseClient(t, cache, 0)
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := api.List(ctx)
+		assert.NoError(err)
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(int32(2), counts[http.MethodGet].Load())
+
+	_, err := api.Read(ctx, "license-1")
+	assert.NoError(err)
+	cache.Purge()
+	_, err = api.Read(ctx, "license-1")
+	assert.NoError(err)
+	assert.Equal(int32(4), counts[http.MethodGet].Load())
+}
+
+func TestCache_Singleflight(t *testing.T) {
+	assert := require.New(t)
+	client, counts := newCountingLicenseClient(t, NewCache(CacheOptions{}), 200*time.Millisecond)
+	api := NewLicenseOp(client)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, err := api.Read(context.Background(), "license-1")
+			assert.NoError(err)
+		})
+	}
+	wg.Wait()
+	assert.Equal(int32(1), counts[http.MethodGet].Load())
+}
+
+func TestCache_DeepCopy(t *testing.T) {
+	assert := require.New(t)
+	client, counts := newCountingLicenseClient(t, NewCache(CacheOptions{TTL: time.Hour}), 0)
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	first, err := api.Read(ctx, "license-1")
+	assert.NoError(err)
+	first.Tags[0] = "changed by caller"
+	*first.Description = "changed by caller"
+	list, err := api.List(ctx)
+	assert.NoError(err)
+	list[0].Tags[0] = "changed by caller"
+
+	second, err := api.Read(ctx, "license-1")
+	assert.NoError(err)
+	assert.Equal([]string{"tag1"}, second.Tags)
+	assert.NotEqual("changed by caller", *second.Description)
+	list, err = api.List(ctx)
+	assert.NoError(err)
+	assert.Equal([]string{"tag1"}, list[0].Tags)
+	assert.Equal(int32(2), counts[http.MethodGet].Load())
+}
+
+func TestCache_SingleflightCancel(t *testing.T) {
+	assert := require.New(t)
+	client, counts := newCountingLicenseClient(t, NewCache(CacheOptions{}), 200*time.Millisecond)
+	api := NewLicenseOp(client)
+
+	// the first caller gives up, but the caller that joined its flight still gets the result
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := api.Read(ctx, "license-1")
+		first <- err
+	}()
+	assert.Eventually(func() bool { return counts[http.MethodGet].Load() == 1 }, 10*time.Second, time.Millisecond)
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := api.Read(context.Background(), "license-1")
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(<-first, context.Canceled)
+	assert.NoError(<-second)
+	assert.Equal(int32(1), counts[http.MethodGet].Load())
+}
+
+func TestCache_FetchTimeout(t *testing.T) {
+	assert := require.New(t)
+	client, _ := newCountingLicenseClient(t, NewCache(CacheOptions{FetchTimeout: 50 * time.Millisecond}), 500*time.Millisecond)
+
+	// the shared fetch does not inherit a deadline, so the cache bounds it itself
+	start := time.Now()
+	_, err := NewLicenseOp(client).Read(context.Background(), "license-1")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.Less(time.Since(start), 400*time.Millisecond)
+}
@@ -0,0 +1,189 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-faster/errors"
+	ht "github.com/ogen-go/ogen/http"
+	ogen "github.com/ogen-go/ogen/validate"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// Call 各Opのメソッド呼び出し1回分の情報
+//
+// 証明書やピアのSecretKeyといった秘密情報は含まない。
+type Call struct {
+	// Operation 生成コード上の操作名
+	Operation v1.OperationName
+
+	// Method ラッパー上のメソッド名。"CloudHSM.Create"など
+	Method string
+
+	// Zone 接続先のゾーン。APIルートURLから判別できない場合は空
+	Zone string
+
+	// 操作対象のリソースID。該当しないものは空
+	CloudHSMID string
+	ClientID   string
+	PeerID     string
+	LicenseID  string
+
+	// Attempts HTTPリクエストを送った回数
+	Attempts int
+
+	// StatusCode 最後に受け取ったHTTPレスポンスのステータスコード
+	StatusCode int
+}
+
+// recordCreated 作成したリソースのIDをfieldに記録する
+//
+// Createではリクエストの時点でIDが分からない。インターセプタやログからも参照できるよう、
+// 成功したレスポンスを受け取ったらすぐにこれで記録する。
+func (c *Call) recordCreated(field *string, id string) {
+	*field = id
+}
+
+// Interceptor 各Opのメソッド呼び出しを包む処理
+//
+// nextを呼ぶと実際の呼び出し(流量制御や再試行を含む)が行われる。
+// callの内容はnextから戻った時点で更新されている。
+type Interceptor func(ctx context.Context, call *Call, next func(context.Context) error) error
+
+// WithInterceptor 各Opのメソッド呼び出しにInterceptorを適用する
+//
+// 複数指定した場合は先に指定したものほど外側で動く。
+func WithInterceptor(i ...Interceptor) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.interceptors = append(cfg.interceptors, i...)
+	}
+}
+
+// WithHTTPClientMiddleware v1.Clientが用いるHTTPクライアントを包む
+//
+// 複数指定した場合は先に指定したものほど外側で動く。
+func WithHTTPClientMiddleware(m ...func(ht.Client) ht.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.middlewares = append(cfg.middlewares, m...)
+	}
+}
+
+type callKey struct{}
+
+// CallFromContext 実行中のメソッド呼び出しの情報
+//
+// WithHTTPClientMiddlewareで包んだHTTPクライアントの中から参照できる。
+func CallFromContext(ctx context.Context) (*Call, bool) {
+	c, ok := ctx.Value(callKey{}).(*Call)
+	return c, ok
+}
+
+// StatusCode エラーがHTTPステータスコードによるものであればそのコードを返す
+func StatusCode(err error) (int, bool) {
+	if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); ok {
+		return e.StatusCode, true
+	}
+	return 0, false
+}
+
+// exchange 1回のHTTP往復について、生成コードのエラーからは失われる情報を控えておく
+type exchange struct {
+	statusCode int
+	header     http.Header
+}
+
+type exchangeKey struct{}
+
+// observingDoer contextにCallやexchangeがあればレスポンスの情報を書き込む
+type observingDoer struct {
+	next ht.Client
+}
+
+func (d *observingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resp, err := d.next.Do(req)
+	if c, ok := CallFromContext(ctx); ok {
+		c.Attempts++
+		if resp != nil {
+			c.StatusCode = resp.StatusCode
+		}
+	}
+	if x, ok := ctx.Value(exchangeKey{}).(*exchange); ok && resp != nil {
+		x.statusCode = resp.StatusCode
+		x.header = resp.Header
+	}
+	return resp, err
+}
+
+// nonIdempotent 再試行が既定では安全でない操作
+var nonIdempotent = map[v1.OperationName]bool{
+	v1.CloudhsmCloudhsmsCreateOperation:        true,
+	v1.CloudhsmCloudhsmsClientsCreateOperation: true,
+	v1.CloudhsmCloudhsmsPeersCreateOperation:   true,
+	v1.CloudhsmLicensesCreateOperation:         true,
+}
+
+// call 生成コードのメソッド呼び出しをラッパー層の共通処理で包む
+func call[T any](ctx context.Context, s *settings, c *Call, f func(context.Context) (T, error)) (T, error) {
+	var ret T
+	err := s.invoke(ctx, c, func(ctx context.Context) (err error) {
+		ret, err = f(ctx)
+		return err
+	})
+	return ret, err
+}
+
+func (s *settings) invoke(ctx context.Context, c *Call, f func(context.Context) error) error {
+	c.Zone = s.zone
+	ctx = context.WithValue(ctx, callKey{}, c)
+
+	next := s.attempt(c, f)
+	if p := s.retry; p != nil && (!nonIdempotent[c.Operation] || p.RetryCreate) {
+		attempt := next
+		next = func(ctx context.Context) error {
+			return p.do(ctx, attempt, func(n int, delay time.Duration, err error) {
+				if s.logger != nil {
+					logRetry(ctx, s.logger, c, n, delay, err)
+				}
+			})
+		}
+	}
+
+	for j := len(s.interceptors) - 1; j >= 0; j-- {
+		i, inner := s.interceptors[j], next
+		next = func(ctx context.Context) error { return i(ctx, c, inner) }
+	}
+	if s.logger != nil {
+		next = logging(s.logger, c, next)
+	}
+
+	return next(ctx)
+}
+
+// attempt 1回分の試行。流量制御が設定されていればそれに従う
+func (s *settings) attempt(c *Call, f func(context.Context) error) func(context.Context) error {
+	if s.limiter == nil {
+		return f
+	}
+	return func(ctx context.Context) error {
+		if err := s.limiter.Wait(ctx, c.Operation); err != nil {
+			return err
+		}
+		return f(ctx)
+	}
+}
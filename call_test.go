@@ -0,0 +1,137 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	ht "github.com/ogen-go/ogen/http"
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestInterceptor_Call(t *testing.T) {
+	assert := require.New(t)
+	var trace []string
+	var seen Call
+
+	interceptor := func(name string) Interceptor {
+		return func(ctx context.Context, c *Call, next func(context.Context) error) error {
+			trace = append(trace, name+">")
+			err := next(ctx)
+			trace = append(trace, "<"+name)
+			seen = *c
+			return err
+		}
+	}
+	middleware := func(next ht.Client) ht.Client {
+		return doerFunc(func(req *http.Request) (*http.Response, error) {
+			c, ok := CallFromContext(req.Context())
+			assert.True(ok)
+			trace = append(trace, "http:"+c.Method)
+			return next.Do(req)
+		})
+	}
+
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, TemplateWrappedCloudHSMClient())
+	}),
+		WithInterceptor(interceptor("outer"), interceptor("inner")),
+		WithHTTPClientMiddleware(middleware),
+	)
+	api, err := NewClientOp(client, &TemplatePartition)
+	assert.NoError(err)
+
+	_, err = api.Read(context.Background(), "client-1")
+	assert.NoError(err)
+	assert.Equal([]string{"outer>", "inner>", "http:Client.Read", "<inner", "<outer"}, trace)
+	assert.Equal(v1.CloudhsmCloudhsmsClientsRetrieveOperation, seen.Operation)
+	assert.Equal(TemplateCloudHSM.ID, seen.CloudHSMID)
+	assert.Equal("client-1", seen.ClientID)
+	assert.Equal(1, seen.Attempts)
+	assert.Equal(http.StatusOK, seen.StatusCode)
+}
+
+func TestInterceptor_Error(t *testing.T) {
+	assert := require.New(t)
+	var seen Call
+	var got error
+
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+	}), WithInterceptor(func(ctx context.Context, c *Call, next func(context.Context) error) error {
+		got = next(ctx)
+		seen = *c
+		return got
+	}))
+
+	err := NewLicenseOp(client).Delete(context.Background(), "license-1")
+	assert.Error(err)
+	assert.Equal("license-1", seen.LicenseID)
+	assert.Equal(http.StatusNotFound, seen.StatusCode)
+
+	code, ok := StatusCode(got)
+	assert.True(ok)
+	assert.Equal(http.StatusNotFound, code)
+}
+
+func TestInterceptor_CreatedID(t *testing.T) {
+	assert := require.New(t)
+	var seen []Call
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := strings.TrimSuffix(r.URL.Path, "/"); {
+		case strings.HasSuffix(p, "/clients"):
+			c := TemplateCreateCloudHSMClient
+			c.SetID("client-1")
+			respondJSON(w, http.StatusCreated, v1.WrappedCreateCloudHSMClient{Client: c})
+		case strings.HasSuffix(p, "/licenses"):
+			l := TemplateCreateLicense
+			l.SetID("license-1")
+			respondJSON(w, http.StatusCreated, v1.WrappedCreateCloudHSMSoftwareLicense{License: v1.NewOptCreateCloudHSMSoftwareLicense(l)})
+		default:
+			h := TemplateCreateCloudHSM
+			h.SetID("hsm-1")
+			respondJSON(w, http.StatusCreated, v1.WrappedCreateCloudHSM{CloudHSM: h})
+		}
+	}), WithInterceptor(func(ctx context.Context, c *Call, next func(context.Context) error) error {
+		err := next(ctx)
+		seen = append(seen, *c)
+		return err
+	}))
+	ctx := context.Background()
+
+	// no audit hook is configured, so the IDs must come from the response itself
+	_, err := NewCloudHSMOp(client).Create(ctx, CloudHSMCreateParams{Name: "hsm"})
+	assert.NoError(err)
+	clientOp, err := NewClientOp(client, &TemplatePartition)
+	assert.NoError(err)
+	_, err = clientOp.Create(ctx, CloudHSMClientCreateParams{Name: "client"})
+	assert.NoError(err)
+	_, err = NewLicenseOp(client).Create(ctx, CloudHSMSoftwareLicenseCreateParams{Name: "license"})
+	assert.NoError(err)
+
+	assert.Len(seen, 3)
+	assert.Equal("hsm-1", seen[0].CloudHSMID)
+	assert.Equal("client-1", seen[1].ClientID)
+	assert.Equal("license-1", seen[2].LicenseID)
+}
@@ -0,0 +1,227 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// DeleteCascadeOptions CloudHSMOp.DeleteCascadeの動作を指定する
+type DeleteCascadeOptions struct {
+	// DryRun 削除対象を報告するだけで、実際には削除しない
+	DryRun bool
+
+	// Force 削除保護を無視する
+	Force bool
+
+	// PollInterval ピアの後始末(CLEANING)が終わったかを確かめる間隔。0なら5秒
+	PollInterval time.Duration
+
+	// Progress 進捗の通知先。nilなら通知しない
+	Progress func(CascadeEvent)
+}
+
+// CascadePhase CascadeEventの段階
+type CascadePhase string
+
+const (
+	// CascadeDeleting 削除を始める(DryRunの場合は削除する予定である)
+	CascadeDeleting CascadePhase = "deleting"
+
+	// CascadeDeleted 削除を終えた
+	CascadeDeleted CascadePhase = "deleted"
+
+	// CascadeWaiting ピアの後始末を待っている
+	CascadeWaiting CascadePhase = "waiting"
+)
+
+// CascadeEvent CloudHSMOp.DeleteCascadeの進捗
+type CascadeEvent struct {
+	// Resource "Client"、"Peer"、"CloudHSM"のいずれか
+	Resource string
+
+	// PartitionID 削除するパーティション。ClientとPeerのイベントでも設定される
+	PartitionID PartitionID
+
+	// ClientID Resourceが"Client"の場合のみ設定される
+	ClientID ClientID
+
+	// PeerID Resourceが"Peer"の場合のみ設定される
+	PeerID RouterID
+
+	Phase  CascadePhase
+	DryRun bool
+}
+
+// ID Resourceに応じたリソースのID
+func (e CascadeEvent) ID() string {
+	switch e.Resource {
+	case "Client":
+		return string(e.ClientID)
+	case "Peer":
+		return string(e.PeerID)
+	default:
+		return string(e.PartitionID)
+	}
+}
+
+// DeleteCascade パーティションをクライアント、ピアとともに削除する
+//
+// クライアント、ピアの順に削除し、ピアの後始末(CLEANING)が終わるのを待ってからパーティションを削除する。
+// 削除保護のうちタグと作成日時による条件は、何かを削除する前に判定する。
+func (op *CloudHSMOp) DeleteCascade(ctx context.Context, id PartitionID, opts DeleteCascadeOptions) error {
+	hsm, err := op.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if p := op.s.protection; p != nil && !opts.Force {
+		if reasons := p.reasons(hsm.Tags, hsm.CreatedAt); len(reasons) > 0 {
+			return NewError("CloudHSM.DeleteCascade", &ProtectedError{ID: string(id), Reasons: reasons})
+		}
+	}
+
+	// クライアントやピアは利用可能なパーティションでなければ扱えない
+	if hsm.Available() {
+		if err := op.deleteClients(ctx, hsm, opts); err != nil {
+			return err
+		}
+		if err := op.deletePeers(ctx, hsm, opts); err != nil {
+			return err
+		}
+	}
+
+	opts.report(CascadeEvent{Resource: "CloudHSM", PartitionID: id, Phase: CascadeDeleting})
+	if opts.DryRun {
+		return nil
+	}
+
+	// タグと作成日時による削除保護は判定済み。WithDryRunの場合はクライアントやピアが
+	// 実際には削除されていないので、残っていることを理由に拒否させない
+	var dopts []DeleteOption
+	if opts.Force || op.s.dryRun {
+		dopts = append(dopts, WithForce())
+	}
+	if err := op.Delete(ctx, id, dopts...); err != nil {
+		return err
+	}
+	opts.report(CascadeEvent{Resource: "CloudHSM", PartitionID: id, Phase: CascadeDeleted})
+	return nil
+}
+
+func (op *CloudHSMOp) deleteClients(ctx context.Context, hsm *Partition, opts DeleteCascadeOptions) error {
+	api, err := NewClientOp(op.client, hsm)
+	if err != nil {
+		return NewError("CloudHSM.DeleteCascade", err)
+	}
+
+	clients, err := api.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range clients {
+		opts.report(CascadeEvent{Resource: "Client", PartitionID: hsm.ID, ClientID: c.ID, Phase: CascadeDeleting})
+		if opts.DryRun {
+			continue
+		}
+		if err := api.Delete(ctx, c.ID); err != nil {
+			return err
+		}
+		opts.report(CascadeEvent{Resource: "Client", PartitionID: hsm.ID, ClientID: c.ID, Phase: CascadeDeleted})
+	}
+	return nil
+}
+
+func (op *CloudHSMOp) deletePeers(ctx context.Context, hsm *Partition, opts DeleteCascadeOptions) error {
+	api, err := NewPeerOp(op.client, hsm)
+	if err != nil {
+		return NewError("CloudHSM.DeleteCascade", err)
+	}
+
+	peers, err := api.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range peers {
+		opts.report(CascadeEvent{Resource: "Peer", PartitionID: hsm.ID, PeerID: p.ID, Phase: CascadeDeleting})
+		if opts.DryRun {
+			continue
+		}
+		// 後始末中のピアは既に削除を受け付けている
+		if p.Status == v1.CloudHSMPeerStatusCLEANING {
+			continue
+		}
+		if err := api.Delete(ctx, p.ID); err != nil {
+			return err
+		}
+	}
+	// WithDryRunの場合は後始末が始まらないので待たない
+	if opts.DryRun || op.s.dryRun || len(peers) == 0 {
+		return nil
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	remaining := peers
+	for {
+		current, err := api.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		left := map[RouterID]bool{}
+		for _, p := range current {
+			left[p.ID] = true
+		}
+		var waiting []Peer
+		for _, p := range remaining {
+			if left[p.ID] {
+				waiting = append(waiting, p)
+			} else {
+				opts.report(CascadeEvent{Resource: "Peer", PartitionID: hsm.ID, PeerID: p.ID, Phase: CascadeDeleted})
+			}
+		}
+		if len(waiting) == 0 {
+			return nil
+		}
+		remaining = waiting
+
+		for _, p := range waiting {
+			opts.report(CascadeEvent{Resource: "Peer", PartitionID: hsm.ID, PeerID: p.ID, Phase: CascadeWaiting})
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return NewError("CloudHSM.DeleteCascade", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (opts *DeleteCascadeOptions) report(e CascadeEvent) {
+	if opts.Progress != nil {
+		e.DryRun = opts.DryRun
+		opts.Progress(e)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// cascadeServer keeps a partition with clients and peers. A deleted peer
+// stays CLEANING for one more listing before it disappears.
+type cascadeServer struct {
+	mu      sync.Mutex
+	clients []v1.CloudHSMClient
+	peers   []v1.CloudHSMPeer
+	deleted []string
+}
+
+func (s *cascadeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodDelete:
+		s.deleted = append(s.deleted, path.Base(path.Dir(p))+"/"+path.Base(p))
+		if strings.Contains(p, "/clients/") {
+			s.clients = s.clients[1:]
+		} else if strings.Contains(p, "/peers/") {
+			for i := range s.peers {
+				if s.peers[i].ID == path.Base(p) {
+					s.peers[i].Status = v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusCLEANING)
+				}
+			}
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	case strings.HasSuffix(p, "/clients"):
+		respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMClientList{
+			Count: len(s.clients), From: v1.NewOptInt(0), Total: v1.NewOptInt(len(s.clients)),
+			Clients: append([]v1.CloudHSMClient{}, s.clients...),
+		})
+	case strings.HasSuffix(p, "/peers"):
+		respondJSON(w, http.StatusOK, v1.CloudHSMPeerList{Peers: append([]v1.CloudHSMPeer{}, s.peers...)})
+		// Peers that were CLEANING when listed are gone by the next listing.
+		var rest []v1.CloudHSMPeer
+		for _, peer := range s.peers {
+			if peer.Status.Or("") != v1.CloudHSMPeerStatusCLEANING {
+				rest = append(rest, peer)
+			}
+		}
+		s.peers = rest
+	default:
+		hsm := TemplateCloudHSM
+		hsm.SetID("hsm-1")
+		respondJSON(w, http.StatusOK, v1.WrappedCloudHSM{CloudHSM: hsm})
+	}
+}
+
+func newCascadeServer() *cascadeServer {
+	c1, c2 := TemplateCloudHSMClient, TemplateCloudHSMClient
+	c1.SetID("client-1")
+	c2.SetID("client-2")
+	return &cascadeServer{
+		clients: []v1.CloudHSMClient{c1, c2},
+		peers: []v1.CloudHSMPeer{
+			{ID: "router-1", Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusUP), Routes: []string{}},
+		},
+	}
+}
+
+func TestDeleteCascade(t *testing.T) {
+	assert := require.New(t)
+	sv := newCascadeServer()
+	client := newTestClientWithHandler(t, sv)
+
+	var events []CascadeEvent
+	err := NewCloudHSMOp(client).DeleteCascade(context.Background(), "hsm-1", DeleteCascadeOptions{
+		PollInterval: time.Millisecond,
+		Progress:     func(e CascadeEvent) { events = append(events, e) },
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"clients/client-1", "clients/client-2", "peers/router-1", "cloudhsms/hsm-1"}, sv.deleted)
+
+	var phases []string
+	for _, e := range events {
+		phases = append(phases, e.Resource+":"+e.ID()+":"+string(e.Phase))
+	}
+	assert.Equal([]string{
+		"Client:client-1:deleting", "Client:client-1:deleted",
+		"Client:client-2:deleting", "Client:client-2:deleted",
+		"Peer:router-1:deleting", "Peer:router-1:waiting", "Peer:router-1:deleted",
+		"CloudHSM:hsm-1:deleting", "CloudHSM:hsm-1:deleted",
+	}, phases)
+}
+
+func TestDeleteCascade_DryRun(t *testing.T) {
+	assert := require.New(t)
+	sv := newCascadeServer()
+	client := newTestClientWithHandler(t, sv)
+
+	var events []CascadeEvent
+	err := NewCloudHSMOp(client).DeleteCascade(context.Background(), "hsm-1", DeleteCascadeOptions{
+		DryRun:   true,
+		Progress: func(e CascadeEvent) { events = append(events, e) },
+	})
+	assert.NoError(err)
+	assert.Empty(sv.deleted)
+	assert.Len(events, 4)
+	for _, e := range events {
+		assert.True(e.DryRun)
+		assert.Equal(CascadeDeleting, e.Phase)
+	}
+}
+
+func TestDeleteCascade_Protected(t *testing.T) {
+	assert := require.New(t)
+	sv := newCascadeServer()
+	client := newTestClientWithHandler(t, sv, WithDeletionProtection(DeletionProtection{Tag: TemplateTags[0]}))
+
+	err := NewCloudHSMOp(client).DeleteCascade(context.Background(), "hsm-1", DeleteCascadeOptions{})
+	assert.ErrorIs(err, ErrDeletionProtected)
+	assert.Empty(sv.deleted)
+}
+
+func TestDeleteCascade_WithDryRun(t *testing.T) {
+	assert := require.New(t)
+	sv := newCascadeServer()
+	client := newTestClientWithHandler(t, sv,
+		WithDryRun(),
+		WithDeletionProtection(DeletionProtection{RequireEmpty: true}),
+		WithLogger(slog.New(slog.DiscardHandler)),
+	)
+
+	// clients and peers are not really deleted, so RequireEmpty must not reject the partition
+	var events []CascadeEvent
+	err := NewCloudHSMOp(client).DeleteCascade(context.Background(), "hsm-1", DeleteCascadeOptions{
+		Progress: func(e CascadeEvent) { events = append(events, e) },
+	})
+	assert.NoError(err)
+	assert.Empty(sv.deleted)
+	assert.Equal(CascadeEvent{Resource: "CloudHSM", PartitionID: "hsm-1", Phase: CascadeDeleted}, events[len(events)-1])
+}
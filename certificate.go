@@ -24,40 +24,69 @@ import (
 )
 
 type ClientAPI interface {
-	List(ctx context.Context) ([]v1.CloudHSMClient, error)
-	Create(ctx context.Context, request CloudHSMClientCreateParams) (*v1.CloudHSMClient, error)
-	Read(ctx context.Context, id string) (*v1.CloudHSMClient, error)
-	Update(ctx context.Context, id string, params CloudHSMClientUpdateParams) (*v1.CloudHSMClient, error)
-	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]Client, error)
+	Create(ctx context.Context, request CloudHSMClientCreateParams) (*Client, error)
+	Read(ctx context.Context, id ClientID) (*Client, error)
+	Update(ctx context.Context, id ClientID, params CloudHSMClientUpdateParams) (*Client, error)
+	Delete(ctx context.Context, id ClientID) error
+	CreateMany(ctx context.Context, params []CloudHSMClientCreateParams, opts BulkOptions) (*BulkResult[Client, ClientID], error)
+	DeleteMany(ctx context.Context, ids []ClientID, opts BulkOptions) (*BulkResult[Client, ClientID], error)
 }
 
 var _ ClientAPI = (*ClientOp)(nil)
 
 type ClientOp struct {
 	client *v1.Client
-	hsm    *v1.CloudHSM
+	hsm    *Partition
+	s      *settings
 }
 
-func NewClientOp(client *v1.Client, hsm *v1.CloudHSM) (ClientAPI, error) {
-	if hsm.GetAvailability() == v1.AvailabilityEnumAvailable {
-		return &ClientOp{
+func NewClientOp(client *v1.Client, hsm *Partition) (ClientAPI, error) {
+	if hsm.Available() {
+		op := &ClientOp{
 			client: client,
 			hsm:    hsm,
-		}, nil
+			s:      settingsOf(client),
+		}
+		if c := op.s.cache; c != nil {
+			return &cachedClientOp{next: op, cache: c, prefix: "client:" + string(hsm.ID) + ":"}, nil
+		}
+		return op, nil
 	}
 	return nil, errors.New("CloudHSM unavailable")
 }
 
-func (op *ClientOp) List(ctx context.Context) ([]v1.CloudHSMClient, error) {
-	resp, err := op.client.CloudhsmCloudhsmsClientsList(
-		ctx,
-		v1.CloudhsmCloudhsmsClientsListParams{
-			CloudhsmResourceID: op.hsm.GetID(),
-		},
-	)
+func (op *ClientOp) newCall(method string, name v1.OperationName, id ClientID) *Call {
+	return &Call{Operation: name, Method: method, CloudHSMID: string(op.hsm.ID), ClientID: string(id)}
+}
+
+// before 監査記録に残す操作前の状態。CloudHSMOp.beforeと同じく生成コードを直接呼ぶ
+func (op *ClientOp) before(id ClientID) func(context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		resp, err := op.client.CloudhsmCloudhsmsClientsRetrieve(ctx, v1.CloudhsmCloudhsmsClientsRetrieveParams{
+			CloudhsmResourceID: string(op.hsm.ID),
+			ID:                 string(id),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ret := ClientFromCloudHSMClient(&resp.Client)
+		return &ret, nil
+	}
+}
+
+func (op *ClientOp) List(ctx context.Context) ([]Client, error) {
+	resp, err := call(ctx, op.s, op.newCall("Client.List", v1.CloudhsmCloudhsmsClientsListOperation, ""), func(ctx context.Context) (*v1.PaginatedCloudHSMClientList, error) {
+		return op.client.CloudhsmCloudhsmsClientsList(
+			ctx,
+			v1.CloudhsmCloudhsmsClientsListParams{
+				CloudhsmResourceID: string(op.hsm.ID),
+			},
+		)
+	})
 
 	if err == nil {
-		return resp.GetClients(), nil
+		return convertAll(resp.GetClients(), ClientFromCloudHSMClient), nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("Client.List", 0, err)
 	} else {
@@ -70,33 +99,34 @@ type CloudHSMClientCreateParams struct {
 	Certificate string
 }
 
-func (op *ClientOp) Create(ctx context.Context, p CloudHSMClientCreateParams) (*v1.CloudHSMClient, error) {
-	resp, err := op.client.CloudhsmCloudhsmsClientsCreate(
-		ctx,
-		&v1.WrappedCreateCloudHSMClient{
-			Client: v1.CreateCloudHSMClient{
-				Name:         p.Name,
-				Certificate:  p.Certificate,
-				Availability: v1.AvailabilityEnumPrecreate,
+func (op *ClientOp) Create(ctx context.Context, p CloudHSMClientCreateParams) (*Client, error) {
+	c := op.newCall("Client.Create", v1.CloudhsmCloudhsmsClientsCreateOperation, "")
+	resp, err := mutate(ctx, op.s, c, nil, func(ctx context.Context) (*v1.WrappedCreateCloudHSMClient, error) {
+		resp, err := op.client.CloudhsmCloudhsmsClientsCreate(
+			ctx,
+			&v1.WrappedCreateCloudHSMClient{
+				Client: v1.CreateCloudHSMClient{
+					Name:         p.Name,
+					Certificate:  p.Certificate,
+					Availability: v1.AvailabilityEnumPrecreate,
+				},
+			},
+			v1.CloudhsmCloudhsmsClientsCreateParams{
+				CloudhsmResourceID: string(op.hsm.ID),
 			},
-		},
-		v1.CloudhsmCloudhsmsClientsCreateParams{
-			CloudhsmResourceID: op.hsm.GetID(),
-		},
-	)
+		)
+		if err == nil {
+			c.recordCreated(&c.ClientID, resp.Client.GetID())
+		}
+		return resp, err
+	}, func(_ *Call, resp *v1.WrappedCreateCloudHSMClient) any {
+		ret := ClientFromCreateCloudHSMClient(&resp.Client)
+		return &ret
+	})
 
 	if err == nil {
-		c := resp.GetClient()
-		// Convert v1.CreateCloudHSMClient to v1.CloudHSMClient
-		client := v1.CloudHSMClient{
-			ID:           c.ID,
-			CreatedAt:    c.CreatedAt,
-			ModifiedAt:   c.ModifiedAt,
-			Availability: c.Availability,
-			Name:         c.Name,
-			Certificate:  c.Certificate,
-		}
-		return &client, nil
+		ret := ClientFromCreateCloudHSMClient(&resp.Client)
+		return &ret, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("Client.Create", 0, err)
 	} else if e.StatusCode == http.StatusUnprocessableEntity {
@@ -106,18 +136,20 @@ func (op *ClientOp) Create(ctx context.Context, p CloudHSMClientCreateParams) (*
 	}
 }
 
-func (op *ClientOp) Read(ctx context.Context, id string) (*v1.CloudHSMClient, error) {
-	resp, err := op.client.CloudhsmCloudhsmsClientsRetrieve(
-		ctx,
-		v1.CloudhsmCloudhsmsClientsRetrieveParams{
-			CloudhsmResourceID: op.hsm.GetID(),
-			ID:                 id,
-		},
-	)
+func (op *ClientOp) Read(ctx context.Context, id ClientID) (*Client, error) {
+	resp, err := call(ctx, op.s, op.newCall("Client.Read", v1.CloudhsmCloudhsmsClientsRetrieveOperation, id), func(ctx context.Context) (*v1.WrappedCloudHSMClient, error) {
+		return op.client.CloudhsmCloudhsmsClientsRetrieve(
+			ctx,
+			v1.CloudhsmCloudhsmsClientsRetrieveParams{
+				CloudhsmResourceID: string(op.hsm.ID),
+				ID:                 string(id),
+			},
+		)
+	})
 
 	if err == nil {
-		client := resp.GetClient()
-		return &client, nil
+		ret := ClientFromCloudHSMClient(&resp.Client)
+		return &ret, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("Client.Read", 0, err)
 	} else if e.StatusCode == http.StatusNotFound {
@@ -131,26 +163,31 @@ type CloudHSMClientUpdateParams struct {
 	Name string
 }
 
-func (op *ClientOp) Update(ctx context.Context, id string, p CloudHSMClientUpdateParams) (*v1.CloudHSMClient, error) {
-	resp, err := op.client.CloudhsmCloudhsmsClientsUpdate(
-		ctx,
-		&v1.WrappedCloudHSMClient{
-			Client: v1.CloudHSMClient{
-				Name: p.Name,
-
-				// This cannot be updated but zero is invalid...
-				Availability: v1.AvailabilityEnumAvailable,
+func (op *ClientOp) Update(ctx context.Context, id ClientID, p CloudHSMClientUpdateParams) (*Client, error) {
+	resp, err := mutate(ctx, op.s, op.newCall("Client.Update", v1.CloudhsmCloudhsmsClientsUpdateOperation, id), op.before(id), func(ctx context.Context) (*v1.WrappedCloudHSMClient, error) {
+		return op.client.CloudhsmCloudhsmsClientsUpdate(
+			ctx,
+			&v1.WrappedCloudHSMClient{
+				Client: v1.CloudHSMClient{
+					Name: p.Name,
+
+					// This cannot be updated but zero is invalid...
+					Availability: v1.AvailabilityEnumAvailable,
+				},
 			},
-		},
-		v1.CloudhsmCloudhsmsClientsUpdateParams{
-			CloudhsmResourceID: op.hsm.GetID(),
-			ID:                 id,
-		},
-	)
+			v1.CloudhsmCloudhsmsClientsUpdateParams{
+				CloudhsmResourceID: string(op.hsm.ID),
+				ID:                 string(id),
+			},
+		)
+	}, func(_ *Call, resp *v1.WrappedCloudHSMClient) any {
+		ret := ClientFromCloudHSMClient(&resp.Client)
+		return &ret
+	})
 
 	if err == nil {
-		client := resp.GetClient()
-		return &client, nil
+		ret := ClientFromCloudHSMClient(&resp.Client)
+		return &ret, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("Client.Update", 0, err)
 	} else if e.StatusCode == http.StatusUnprocessableEntity {
@@ -160,14 +197,16 @@ func (op *ClientOp) Update(ctx context.Context, id string, p CloudHSMClientUpdat
 	}
 }
 
-func (op *ClientOp) Delete(ctx context.Context, id string) error {
-	err := op.client.CloudhsmCloudhsmsClientsDestroy(
-		ctx,
-		v1.CloudhsmCloudhsmsClientsDestroyParams{
-			CloudhsmResourceID: op.hsm.GetID(),
-			ID:                 id,
-		},
-	)
+func (op *ClientOp) Delete(ctx context.Context, id ClientID) error {
+	err := op.s.mutate(ctx, op.newCall("Client.Delete", v1.CloudhsmCloudhsmsClientsDestroyOperation, id), op.before(id), func(ctx context.Context) error {
+		return op.client.CloudhsmCloudhsmsClientsDestroy(
+			ctx,
+			v1.CloudhsmCloudhsmsClientsDestroyParams{
+				CloudhsmResourceID: string(op.hsm.ID),
+				ID:                 string(id),
+			},
+		)
+	}, nil)
 
 	if err == nil {
 		return nil
@@ -42,7 +42,7 @@ func TestCloudHSMClientOp_List(t *testing.T) {
 		Clients: []v1.CloudHSMClient{TemplateCloudHSMClient},
 	}
 	client := newTestClient(expected)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 	clients, err := api.List(ctx)
@@ -55,7 +55,7 @@ func TestCloudHSMClientOp_List(t *testing.T) {
 func TestCloudHSMClientOp_Create(t *testing.T) {
 	assert := require.New(t)
 	client := newTestClient(TemplateWrappedCreateCloudHSMClient, http.StatusCreated)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -65,15 +65,15 @@ func TestCloudHSMClientOp_Create(t *testing.T) {
 	})
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateCreateCloudHSMClient.GetName(), res.GetName())
-	assert.Equal(TemplateCreateCloudHSMClient.GetCertificate(), res.GetCertificate())
+	assert.Equal(TemplateCreateCloudHSMClient.Name, res.Name)
+	assert.Equal(TemplateCreateCloudHSMClient.Certificate, res.Certificate)
 }
 
 func TestCloudHSMClientOp_Create_422(t *testing.T) {
 	assert := require.New(t)
 	expected := newErrorResponse("Invalid request body.")
 	client := newTestClient(expected, http.StatusUnprocessableEntity)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -86,14 +86,14 @@ func TestCloudHSMClientOp_Create_422(t *testing.T) {
 func TestCloudHSMClientOp_Read(t *testing.T) {
 	assert := require.New(t)
 	client := newTestClient(&v1.WrappedCloudHSMClient{Client: TemplateCloudHSMClient})
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
 	res, err := api.Read(ctx, "client-1")
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateCloudHSMClient.ID, res.ID)
+	assert.Equal(ClientID(TemplateCloudHSMClient.ID), res.ID)
 	assert.Equal(TemplateCloudHSMClient.Name, res.Name)
 }
 
@@ -101,7 +101,7 @@ func TestCloudHSMClientOp_Read_Error(t *testing.T) {
 	assert := require.New(t)
 	expected := newErrorResponse("not found")
 	client := newTestClient(expected, http.StatusNotFound)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -117,7 +117,7 @@ func TestCloudHSMClientOp_Update(t *testing.T) {
 	updated.Name = "updated-name"
 	updated.Certificate = "updated-cert"
 	client := newTestClient(&v1.WrappedCloudHSMClient{Client: updated})
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -134,7 +134,7 @@ func TestCloudHSMClientOp_Update_422(t *testing.T) {
 	assert := require.New(t)
 	expected := newErrorResponse("Invalid request body.")
 	client := newTestClient(expected, http.StatusUnprocessableEntity)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -147,7 +147,7 @@ func TestCloudHSMClientOp_Update_422(t *testing.T) {
 func TestCloudHSMClientOp_Delete(t *testing.T) {
 	assert := require.New(t)
 	client := newTestClient(nil, http.StatusNoContent)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -159,7 +159,7 @@ func TestCloudHSMClientOp_Delete_400(t *testing.T) {
 	assert := require.New(t)
 	expected := newErrorResponse("Not found")
 	client := newTestClient(expected, http.StatusNotFound)
-	api, err := NewClientOp(client, &TemplateCloudHSM)
+	api, err := NewClientOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -175,10 +175,10 @@ func TestCloudHSMClientIntegrated(t *testing.T) {
 	testutil.PreCheckEnvsFunc("SAKURA_CLOUDHSM_ID")(t)
 
 	ctx := context.Background()
-	hsm, err := NewCloudHSMOp(client).Read(ctx, os.Getenv("SAKURA_CLOUDHSM_ID"))
+	hsm, err := NewCloudHSMOp(client).Read(ctx, PartitionID(os.Getenv("SAKURA_CLOUDHSM_ID")))
 	assert.NoError(err)
 	assert.NotNil(hsm)
-	assert.Equal(v1.AvailabilityEnumAvailable, hsm.GetAvailability())
+	assert.Equal(v1.AvailabilityEnumAvailable, hsm.Availability)
 	api, err := NewClientOp(client, hsm)
 	assert.NoError(err)
 
@@ -196,7 +196,7 @@ func TestCloudHSMClientIntegrated(t *testing.T) {
 
 	// Delete
 	t.Cleanup(func() {
-		err := api.Delete(ctx, created.GetID())
+		err := api.Delete(ctx, created.ID)
 		assert.NoError(err)
 	})
 
@@ -20,6 +20,7 @@ import (
 	"runtime"
 	"strings"
 
+	ht "github.com/ogen-go/ogen/http"
 	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
 	"github.com/sacloud/saclient-go"
 )
@@ -54,60 +55,129 @@ func (this EmptySecuritySource) BasicAuth(ctx context.Context, operationName v1.
 	return v1.BasicAuth{}, nil
 }
 
-func NewClient(client saclient.ClientAPI) (*v1.Client, error) {
-	const path = "api/cloud/1.1/"
+// NewClient saclientの設定とオプションに従ってクライアントを作成する
+//
+// ゾーンはWithZone、saclientの設定、DefaultZoneの順に、
+// エンドポイントはWithEndpoint、saclientの設定、DefaultEndpointの順に決まる。
+func NewClient(client saclient.ClientAPI, opts ...ClientOption) (*v1.Client, error) {
+	cfg := newClientConfig("", opts...)
 	zone := DefaultZone
 	endpoint := DefaultEndpoint
 
+	if client != nil {
+		ep, ec, err := resolveEndpoint(client, cfg.serviceKey)
+
+		if err != nil {
+			return nil, NewError("NewClient", err)
+		}
+
+		endpoint = ep
+		if ec.Zone != "" {
+			zone = ec.Zone
+		}
+	}
+
+	if cfg.endpoint != "" {
+		endpoint = cfg.endpoint
+	}
+
+	if cfg.zone != "" {
+		zone = cfg.zone
+	}
+
+	return NewClientWithApiUrl(zoneAPIURL(endpoint, zone), client, opts...)
+}
+
+// resolveEndpoint saclientの設定からクラウドHSMのエンドポイントを求める
+func resolveEndpoint(client saclient.ClientAPI, serviceKey string) (string, *saclient.EndpointConfig, error) {
+	endpoint := DefaultEndpoint
+
 	cfg, err := client.EndpointConfig()
 
 	if err != nil {
-		return nil, NewError("NewClient", err)
+		return "", nil, err
 	}
 
-	if ep, ok := cfg.Endpoints[ServiceKey]; ok && ep != "" {
+	if ep, ok := cfg.Endpoints[serviceKey]; ok && ep != "" {
 		endpoint = ep
 	}
 
-	if cfg.Zone != "" {
-		zone = cfg.Zone
-	}
+	return endpoint, cfg, nil
+}
 
-	apiUrl := fmt.Sprintf(
+// zoneAPIURL エンドポイントとゾーンからAPIルートURLを組み立てる
+func zoneAPIURL(endpoint, zone string) string {
+	const path = "api/cloud/1.1/"
+
+	return fmt.Sprintf(
 		"%s/%s/%s",
 		strings.TrimSuffix(endpoint, "/"),
 		strings.TrimPrefix(strings.TrimSuffix(zone, "/"), "/"),
 		path,
 	)
-
-	return NewClientWithApiUrl(apiUrl, client)
 }
 
-func NewClientWithApiUrl(apiUrl string, client saclient.ClientAPI) (*v1.Client, error) {
-	dupable, ok := client.(*saclient.Client)
-	if !ok {
-		return nil, NewError("NewClientWithApiUrl", fmt.Errorf("client must be *saclient.Client"))
-	}
-
-	augmented, err := dupable.DupWith(
-		saclient.WithUserAgent(UserAgent),
-		saclient.WithRootURL(apiUrl),
-		saclient.WithBigInt(true),
-		// これはなにか:
-		// EmptySecuritySource.BasicAuth()がBasic認証を生成
-		// しかし実際の通信で必ずしもBasic認証が使われると限らない
-		//　そのあたりをsaclient-go側で吸収させる設定が下記↓
-		saclient.WithForceAutomaticAuthentication(),
-	)
-
-	if err != nil {
-		return nil, NewError("NewClientWithApiUrl", err)
+// NewClientWithApiUrl APIルートURLを指定してクライアントを作成する
+//
+// clientには*saclient.Clientに限らず任意のsaclient.ClientAPI実装を渡せる。
+// *saclient.Clientの場合は従来どおりその設定を引き継いだ複製を用い、
+// それ以外の実装では認証をclient側に任せてリクエストを委譲する。
+// WithHTTPClientを指定した場合はclientにnilを渡してもよい。
+func NewClientWithApiUrl(apiUrl string, client saclient.ClientAPI, opts ...ClientOption) (*v1.Client, error) {
+	cfg := newClientConfig(apiUrl, opts...)
+
+	var doer ht.Client
+	if cfg.httpClient != nil {
+		doer = cfg.newDoer(cfg.httpClient)
+	} else if dupable, ok := client.(*saclient.Client); ok {
+		augmented, err := dupable.DupWith(
+			saclient.WithUserAgent(cfg.userAgent),
+			saclient.WithRootURL(cfg.rootURL),
+			saclient.WithBigInt(true),
+		)
+
+		var settable saclient.ClientOptionAPI
+		if err == nil {
+			var ok bool
+			if settable, ok = augmented.(saclient.ClientOptionAPI); !ok {
+				err = fmt.Errorf("%T does not implement saclient.ClientOptionAPI", augmented)
+			}
+		}
+
+		if err == nil && cfg.security == nil {
+			// これはなにか:
+			// EmptySecuritySource.BasicAuth()がBasic認証を生成
+			// しかし実際の通信で必ずしもBasic認証が使われると限らない
+			//　そのあたりをsaclient-go側で吸収させる設定が下記↓
+			err = settable.SetWith(saclient.WithForceAutomaticAuthentication())
+		}
+
+		if err == nil && cfg.retry != nil {
+			// 再試行はRetryPolicyに任せる
+			err = settable.SetWith(saclient.WithoutRetry())
+		}
+
+		if err != nil {
+			return nil, NewError("NewClientWithApiUrl", err)
+		}
+		doer = augmented
+		if cfg.actor == nil {
+			cfg.actor = profileActor(augmented)
+		}
+	} else if client != nil {
+		doer = cfg.newDoer(client)
+		if cfg.actor == nil {
+			cfg.actor = profileActor(client)
+		}
+	} else {
+		return nil, NewError("NewClientWithApiUrl", fmt.Errorf("either client or WithHTTPClient is required"))
 	}
 
-	d, err := v1.NewClient(apiUrl, EmptySecuritySource{}, v1.WithClient(augmented))
+	d, err := v1.NewClient(cfg.rootURL, cfg.securitySource(), v1.WithClient(cfg.wrap(doer)))
 	if err != nil {
 		return nil, NewError("NewClientWithApiUrl", err)
 	}
 
+	register(d, cfg.settings())
 	return d, nil
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"unsafe"
 
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
 	"github.com/sacloud/saclient-go"
 	"github.com/stretchr/testify/require"
 )
@@ -49,6 +50,10 @@ func TestNewClient_WithZone(tt *testing.T) {
 	require.NotNil(tt, c)
 
 	// この作成したクライアントが本当にtk1aを向いているかを確認するのがやや困難である
+	require.Contains(tt, serverURLOf(tt, c), "/zone/tk1a/")
+}
+
+func serverURLOf(tt *testing.T, c *v1.Client) string {
 	q := reflect.ValueOf(c)
 	w := q.Elem()
 	e := w.FieldByName("serverURL")
@@ -60,5 +65,50 @@ func TestNewClient_WithZone(tt *testing.T) {
 	o, p := i.(*url.URL)
 
 	require.True(tt, p)
-	require.Contains(tt, o.String(), "/zone/tk1a/")
+	return o.String()
+}
+
+func TestNewClient_WithZoneOption(t *testing.T) {
+	client := testingClient.Dup()
+	err := client.SetEnviron([]string{"SAKURA_ZONE=tk1a"})
+	require.NoError(t, err)
+
+	c, err := NewClient(client, WithZone("is1a"))
+	require.NoError(t, err)
+	require.Equal(t, "https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1", serverURLOf(t, c))
+}
+
+func TestNewClient_WithEndpoint(t *testing.T) {
+	c, err := NewClient(testingClient.Dup(), WithEndpoint("https://example.com/zone/"), WithZone("tk1b"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/zone/tk1b/api/cloud/1.1", serverURLOf(t, c))
+}
+
+func TestNewClient_WithServiceKey(t *testing.T) {
+	client := testingClient.Dup()
+	err := client.SetEnviron([]string{"SAKURA_ENDPOINTS_MYHSM=https://myhsm.example.com/"})
+	require.NoError(t, err)
+
+	c, err := NewClient(client, WithServiceKey("myhsm"))
+	require.NoError(t, err)
+	require.Equal(t, "https://myhsm.example.com/is1b/api/cloud/1.1", serverURLOf(t, c))
+}
+
+func TestNewClient_WithRootURL(t *testing.T) {
+	c, err := NewClient(testingClient.Dup(), WithZone("tk1b"), WithRootURL("https://example.com/api/"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/api", serverURLOf(t, c))
+}
+
+func TestNewClientConfig_UserAgentSuffix(t *testing.T) {
+	cfg := newClientConfig("", WithUserAgentSuffix("my-controller/2.0"))
+	require.Equal(t, UserAgent+" my-controller/2.0", cfg.userAgent)
+
+	cfg = newClientConfig("", WithUserAgent("base/1.0"), WithUserAgentSuffix("x"))
+	require.Equal(t, "base/1.0 x", cfg.userAgent)
+}
+
+func TestZoneOf(t *testing.T) {
+	require.Equal(t, "tk1a", zoneOf("https://secure.sakura.ad.jp/cloud/zone/tk1a/api/cloud/1.1/"))
+	require.Equal(t, "", zoneOf("http://127.0.0.1:1234"))
 }
@@ -17,6 +17,7 @@ package cloudhsm
 import (
 	"context"
 	"net/http"
+	"net/netip"
 
 	"github.com/go-faster/errors"
 	ogen "github.com/ogen-go/ogen/validate"
@@ -24,60 +25,96 @@ import (
 )
 
 type CloudHSMAPI interface {
-	List(ctx context.Context) ([]v1.CloudHSM, error)
-	Create(ctx context.Context, request CloudHSMCreateParams) (*v1.CreateCloudHSM, error)
-	Read(ctx context.Context, id string) (*v1.CloudHSM, error)
-	Update(ctx context.Context, id string, params CloudHSMUpdateParams) (*v1.CloudHSM, error)
-	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]Partition, error)
+	Create(ctx context.Context, request CloudHSMCreateParams) (*Partition, error)
+	Read(ctx context.Context, id PartitionID) (*Partition, error)
+	Update(ctx context.Context, id PartitionID, params CloudHSMUpdateParams) (*Partition, error)
+	Delete(ctx context.Context, id PartitionID, opts ...DeleteOption) error
+	DeleteCascade(ctx context.Context, id PartitionID, opts DeleteCascadeOptions) error
 }
 
 var _ CloudHSMAPI = (*CloudHSMOp)(nil)
 
 type CloudHSMOp struct {
 	client *v1.Client
+	s      *settings
 }
 
 func NewCloudHSMOp(client *v1.Client) CloudHSMAPI {
-	return &CloudHSMOp{client: client}
+	op := &CloudHSMOp{client: client, s: settingsOf(client)}
+	if c := op.s.cache; c != nil {
+		return &cachedCloudHSMOp{next: op, cache: c}
+	}
+	return op
+}
+
+func (op *CloudHSMOp) newCall(method string, name v1.OperationName, id PartitionID) *Call {
+	return &Call{Operation: name, Method: method, CloudHSMID: string(id)}
+}
+
+// before 監査記録に残す操作前の状態
+//
+// 監査のための取得で流量制御やインターセプタを通らないよう、生成コードを直接呼ぶ。
+func (op *CloudHSMOp) before(id PartitionID) func(context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		resp, err := op.client.CloudhsmCloudhsmsRetrieve(ctx, v1.CloudhsmCloudhsmsRetrieveParams{ResourceID: string(id)})
+		if err != nil {
+			return nil, err
+		}
+		ret := PartitionFromCloudHSM(&resp.CloudHSM)
+		return &ret, nil
+	}
 }
 
-func (op *CloudHSMOp) List(ctx context.Context) ([]v1.CloudHSM, error) {
-	resp, err := op.client.CloudhsmCloudhsmsList(ctx)
+func (op *CloudHSMOp) List(ctx context.Context) ([]Partition, error) {
+	resp, err := call(ctx, op.s, op.newCall("CloudHSM.List", v1.CloudhsmCloudhsmsListOperation, ""), func(ctx context.Context) (*v1.PaginatedCloudHSMList, error) {
+		return op.client.CloudhsmCloudhsmsList(ctx)
+	})
 	if err != nil {
 		return nil, NewAPIError("CloudHSM.List", 0, err)
 	}
-	return resp.CloudHSMs, nil
+	return convertAll(resp.CloudHSMs, PartitionFromCloudHSM), nil
 }
 
 type CloudHSMCreateParams struct {
 	Name               string
 	Description        *string
 	Tags               []string
-	Ipv4NetworkAddress string
-	Ipv4PrefixLength   int
+	Ipv4NetworkAddress netip.Prefix
 }
 
-func (op *CloudHSMOp) Create(ctx context.Context, p CloudHSMCreateParams) (*v1.CreateCloudHSM, error) {
+func (op *CloudHSMOp) Create(ctx context.Context, p CloudHSMCreateParams) (*Partition, error) {
 	if p.Tags == nil {
 		p.Tags = []string{}
 	}
-	resp, err := op.client.CloudhsmCloudhsmsCreate(
-		ctx,
-		&v1.WrappedCreateCloudHSM{
-			CloudHSM: v1.CreateCloudHSM{
-				Name:               p.Name,
-				Description:        intoOpt[v1.OptString](p.Description),
-				Tags:               p.Tags,
-				Availability:       v1.AvailabilityEnumAvailable,
-				ServiceClass:       v1.ServiceClassEnumCloudCloudhsmPartition,
-				Ipv4NetworkAddress: p.Ipv4NetworkAddress,
-				Ipv4PrefixLength:   p.Ipv4PrefixLength,
+	addr, bits := fromPrefix(p.Ipv4NetworkAddress)
+	c := op.newCall("CloudHSM.Create", v1.CloudhsmCloudhsmsCreateOperation, "")
+	resp, err := mutate(ctx, op.s, c, nil, func(ctx context.Context) (*v1.WrappedCreateCloudHSM, error) {
+		resp, err := op.client.CloudhsmCloudhsmsCreate(
+			ctx,
+			&v1.WrappedCreateCloudHSM{
+				CloudHSM: v1.CreateCloudHSM{
+					Name:               p.Name,
+					Description:        intoOpt[v1.OptString](p.Description),
+					Tags:               p.Tags,
+					Availability:       v1.AvailabilityEnumAvailable,
+					ServiceClass:       v1.ServiceClassEnumCloudCloudhsmPartition,
+					Ipv4NetworkAddress: addr,
+					Ipv4PrefixLength:   bits,
+				},
 			},
-		},
-	)
+		)
+		if err == nil {
+			c.recordCreated(&c.CloudHSMID, resp.CloudHSM.GetID())
+		}
+		return resp, err
+	}, func(_ *Call, resp *v1.WrappedCreateCloudHSM) any {
+		ret := PartitionFromCreateCloudHSM(&resp.CloudHSM)
+		return &ret
+	})
 
 	if err == nil {
-		ret := resp.GetCloudHSM()
+		ret := PartitionFromCreateCloudHSM(&resp.CloudHSM)
 		return &ret, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("CloudHSM.Create", 0, err)
@@ -88,16 +125,18 @@ func (op *CloudHSMOp) Create(ctx context.Context, p CloudHSMCreateParams) (*v1.C
 	}
 }
 
-func (op *CloudHSMOp) Read(ctx context.Context, id string) (*v1.CloudHSM, error) {
-	resp, err := op.client.CloudhsmCloudhsmsRetrieve(
-		ctx,
-		v1.CloudhsmCloudhsmsRetrieveParams{
-			ResourceID: id,
-		},
-	)
+func (op *CloudHSMOp) Read(ctx context.Context, id PartitionID) (*Partition, error) {
+	resp, err := call(ctx, op.s, op.newCall("CloudHSM.Read", v1.CloudhsmCloudhsmsRetrieveOperation, id), func(ctx context.Context) (*v1.WrappedCloudHSM, error) {
+		return op.client.CloudhsmCloudhsmsRetrieve(
+			ctx,
+			v1.CloudhsmCloudhsmsRetrieveParams{
+				ResourceID: string(id),
+			},
+		)
+	})
 
 	if err == nil {
-		ret := resp.GetCloudHSM()
+		ret := PartitionFromCloudHSM(&resp.CloudHSM)
 		return &ret, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("CloudHSM.Read", 0, err)
@@ -112,35 +151,40 @@ type CloudHSMUpdateParams struct {
 	Name               string
 	Description        *string
 	Tags               []string
-	Ipv4NetworkAddress string
-	Ipv4PrefixLength   int
+	Ipv4NetworkAddress netip.Prefix
 }
 
-func (op *CloudHSMOp) Update(ctx context.Context, id string, p CloudHSMUpdateParams) (*v1.CloudHSM, error) {
+func (op *CloudHSMOp) Update(ctx context.Context, id PartitionID, p CloudHSMUpdateParams) (*Partition, error) {
 	if p.Tags == nil {
 		p.Tags = []string{}
 	}
-
-	resp, err := op.client.CloudhsmCloudhsmsUpdate(
-		ctx,
-		&v1.WrappedCloudHSM{
-			CloudHSM: v1.CloudHSM{
-				ServiceClass:       v1.ServiceClassEnumCloudCloudhsmPartition,
-				Availability:       v1.AvailabilityEnumAvailable,
-				Name:               p.Name,
-				Description:        intoOpt[v1.OptString](p.Description),
-				Tags:               p.Tags,
-				Ipv4NetworkAddress: p.Ipv4NetworkAddress,
-				Ipv4PrefixLength:   p.Ipv4PrefixLength,
+	addr, bits := fromPrefix(p.Ipv4NetworkAddress)
+
+	resp, err := mutate(ctx, op.s, op.newCall("CloudHSM.Update", v1.CloudhsmCloudhsmsUpdateOperation, id), op.before(id), func(ctx context.Context) (*v1.WrappedCloudHSM, error) {
+		return op.client.CloudhsmCloudhsmsUpdate(
+			ctx,
+			&v1.WrappedCloudHSM{
+				CloudHSM: v1.CloudHSM{
+					ServiceClass:       v1.ServiceClassEnumCloudCloudhsmPartition,
+					Availability:       v1.AvailabilityEnumAvailable,
+					Name:               p.Name,
+					Description:        intoOpt[v1.OptString](p.Description),
+					Tags:               p.Tags,
+					Ipv4NetworkAddress: addr,
+					Ipv4PrefixLength:   bits,
+				},
+			},
+			v1.CloudhsmCloudhsmsUpdateParams{
+				ResourceID: string(id),
 			},
-		},
-		v1.CloudhsmCloudhsmsUpdateParams{
-			ResourceID: id,
-		},
-	)
+		)
+	}, func(_ *Call, resp *v1.WrappedCloudHSM) any {
+		ret := PartitionFromCloudHSM(&resp.CloudHSM)
+		return &ret
+	})
 
 	if err == nil {
-		ret := resp.GetCloudHSM()
+		ret := PartitionFromCloudHSM(&resp.CloudHSM)
 		return &ret, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("CloudHSM.Update", 0, err)
@@ -151,13 +195,19 @@ func (op *CloudHSMOp) Update(ctx context.Context, id string, p CloudHSMUpdatePar
 	}
 }
 
-func (op *CloudHSMOp) Delete(ctx context.Context, id string) error {
-	err := op.client.CloudhsmCloudhsmsDestroy(
-		ctx,
-		v1.CloudhsmCloudhsmsDestroyParams{
-			ResourceID: id,
-		},
-	)
+func (op *CloudHSMOp) Delete(ctx context.Context, id PartitionID, opts ...DeleteOption) error {
+	if err := op.protect(ctx, id, opts); err != nil {
+		return err
+	}
+
+	err := op.s.mutate(ctx, op.newCall("CloudHSM.Delete", v1.CloudhsmCloudhsmsDestroyOperation, id), op.before(id), func(ctx context.Context) error {
+		return op.client.CloudhsmCloudhsmsDestroy(
+			ctx,
+			v1.CloudhsmCloudhsmsDestroyParams{
+				ResourceID: string(id),
+			},
+		)
+	}, nil)
 
 	if err == nil {
 		return nil
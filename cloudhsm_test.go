@@ -16,9 +16,9 @@ package cloudhsm_test
 
 import (
 	"context"
-	"fmt"
 	"math/rand/v2"
 	"net/http"
+	"net/netip"
 	"testing"
 
 	. "github.com/sacloud/cloudhsm-api-go"
@@ -58,7 +58,7 @@ func TestCloudHSMOp_Read(t *testing.T) {
 	res, err := api.Read(ctx, "12345")
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateWrappedCloudHSM.GetCloudHSM(), *res)
+	assert.Equal(PartitionFromCloudHSM(&TemplateCloudHSM), *res)
 }
 
 func TestCloudHSMOp_Read_404(t *testing.T) {
@@ -90,7 +90,7 @@ func TestCloudHSMOp_Create(t *testing.T) {
 	})
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateWrappedCreateCloudHSM.GetCloudHSM(), *res)
+	assert.Equal(PartitionFromCreateCloudHSM(&TemplateCreateCloudHSM), *res)
 }
 
 func TestCloudHSMOp_Create_422(t *testing.T) {
@@ -122,7 +122,7 @@ func TestCloudHSMOp_Update(t *testing.T) {
 	})
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateWrappedCloudHSM.GetCloudHSM(), *res)
+	assert.Equal(PartitionFromCloudHSM(&TemplateCloudHSM), *res)
 }
 
 func TestCloudHSMOp_Update_400(t *testing.T) {
@@ -172,24 +172,23 @@ func TestCloudHSMIntegrated(t *testing.T) {
 		Name:        testutil.RandomName("test-cloudhsm-", 16, testutil.CharSetAlphaNum),
 		Description: ref(testutil.Random(128, testutil.CharSetAlphaNum)),
 		// This IP address is arbitrary, but recommended to be in the private range.
-		Ipv4NetworkAddress: fmt.Sprintf("172.%d.%d.0", rand.Uint32N(31), rand.Uint32N(255)),
-		Ipv4PrefixLength:   28,
+		Ipv4NetworkAddress: netip.PrefixFrom(netip.AddrFrom4([4]byte{172, byte(rand.Uint32N(31)), byte(rand.Uint32N(255)), 0}), 28),
 	})
 	assert.NoError(err)
 	assert.NotNil(created)
 
 	// Delete
 	t.Cleanup(func() {
-		err := api.Delete(ctx, created.GetID())
+		err := api.Delete(ctx, created.ID)
 		assert.NoError(err)
 	})
 
 	// Read
-	read, err := api.Read(ctx, created.GetID())
+	read, err := api.Read(ctx, created.ID)
 	assert.NoError(err)
 	assert.NotNil(read)
-	assert.Equal(created.GetID(), read.GetID())
-	assert.Equal(created.GetName(), read.GetName())
+	assert.Equal(created.ID, read.ID)
+	assert.Equal(created.Name, read.Name)
 
 	// List
 	cloudhsms, err := api.List(ctx)
@@ -201,12 +200,11 @@ func TestCloudHSMIntegrated(t *testing.T) {
 	newDesc := "updated integration test CloudHSM"
 	updateReq := CloudHSMUpdateParams{
 		Description:        ref(newDesc),
-		Name:               read.GetName(),
+		Name:               read.Name,
 		Ipv4NetworkAddress: read.Ipv4NetworkAddress,
-		Ipv4PrefixLength:   read.Ipv4PrefixLength,
 	}
-	updated, err := api.Update(ctx, created.GetID(), updateReq)
+	updated, err := api.Update(ctx, created.ID, updateReq)
 	assert.NoError(err)
 	assert.NotNil(updated)
-	assert.Equal(newDesc, updated.GetDescription().Or("failure"))
+	assert.Equal(&newDesc, updated.Description)
 }
@@ -0,0 +1,311 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ChangeKind リソースの差分の種類
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// FieldChange 項目1つ分の変化
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// ResourceDiff リソース1つ分の差分
+type ResourceDiff struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Resource "Partition"、"Client"、"Peer"、"License"のいずれか
+	Resource string `json:"resource"`
+
+	// ID 新しい側のID。削除されたリソースの場合は古い側のID
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Partition クライアントとピアの属するパーティションのID
+	Partition string `json:"partition,omitempty"`
+
+	// Changes ChangeChangedの場合に変化した項目
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// InventoryDiff 2つのInventoryの差分
+type InventoryDiff struct {
+	Resources []ResourceDiff `json:"resources"`
+}
+
+// DiffOptions DiffInventoryの動作を指定する
+type DiffOptions struct {
+	// MatchByName パーティション、クライアント、ライセンスをIDではなく名前で対応付ける。
+	// ゾーン間やRestoreの前後で比べる場合に用いる
+	MatchByName bool
+}
+
+// DiffInventory fromからtoへの差分を求める
+//
+// 作成・更新日時は比べない。クライアントの証明書はフィンガープリントで比べる。
+// 追加・削除されたパーティションのクライアントとピアも、それぞれ追加・削除として含める。
+func DiffInventory(from, to *Inventory, opts DiffOptions) *InventoryDiff {
+	d := &InventoryDiff{Resources: []ResourceDiff{}}
+
+	partitionKey := func(p *PartitionRecord) string { return p.ID }
+	licenseKey := func(l *LicenseRecord) string { return l.ID }
+	if opts.MatchByName {
+		partitionKey = func(p *PartitionRecord) string { return p.Name }
+		licenseKey = func(l *LicenseRecord) string { return l.Name }
+	}
+
+	diffRecords(from.Partitions, to.Partitions, partitionKey, func(o, n *PartitionRecord) {
+		d.diffPartition(o, n, opts)
+	})
+	diffRecords(from.Licenses, to.Licenses, licenseKey, func(o, n *LicenseRecord) {
+		switch {
+		case o == nil:
+			d.add(ResourceDiff{Kind: ChangeAdded, Resource: "License", ID: n.ID, Name: n.Name})
+		case n == nil:
+			d.add(ResourceDiff{Kind: ChangeRemoved, Resource: "License", ID: o.ID, Name: o.Name})
+		default:
+			var ch changes
+			ch.compare("Name", o.Name, n.Name)
+			ch.compare("Description", o.Description, n.Description)
+			ch.compareSet("Tags", o.Tags, n.Tags)
+			ch.compare("ServiceClass", o.ServiceClass, n.ServiceClass)
+			d.changed(ResourceDiff{Resource: "License", ID: n.ID, Name: n.Name}, ch)
+		}
+	})
+	return d
+}
+
+func (d *InventoryDiff) diffPartition(o, n *PartitionRecord, opts DiffOptions) {
+	switch {
+	case o == nil:
+		d.add(ResourceDiff{Kind: ChangeAdded, Resource: "Partition", ID: n.ID, Name: n.Name})
+		d.diffChildren(&PartitionRecord{}, n, n.ID, opts)
+	case n == nil:
+		d.add(ResourceDiff{Kind: ChangeRemoved, Resource: "Partition", ID: o.ID, Name: o.Name})
+		d.diffChildren(o, &PartitionRecord{}, o.ID, opts)
+	default:
+		var ch changes
+		ch.compare("Name", o.Name, n.Name)
+		ch.compare("Description", o.Description, n.Description)
+		ch.compareSet("Tags", o.Tags, n.Tags)
+		ch.compare("Availability", o.Availability, n.Availability)
+		ch.compare("Ipv4NetworkAddress", o.Ipv4NetworkAddress, n.Ipv4NetworkAddress)
+		ch.compare("Ipv4PrefixLength", o.Ipv4PrefixLength, n.Ipv4PrefixLength)
+		ch.compare("Ipv4Address", o.Ipv4Address, n.Ipv4Address)
+		ch.compare("LocalRouter", o.localRouterID(), n.localRouterID())
+		d.changed(ResourceDiff{Resource: "Partition", ID: n.ID, Name: n.Name}, ch)
+		d.diffChildren(o, n, n.ID, opts)
+	}
+}
+
+// diffChildren クライアントとピアの差分を求める。ピアは常にルータのIDで対応付ける
+func (d *InventoryDiff) diffChildren(o, n *PartitionRecord, partition string, opts DiffOptions) {
+	clientKey := func(c *ClientRecord) string { return c.ID }
+	if opts.MatchByName {
+		clientKey = func(c *ClientRecord) string { return c.Name }
+	}
+	diffRecords(o.Clients, n.Clients, clientKey, func(oc, nc *ClientRecord) {
+		switch {
+		case oc == nil:
+			d.add(ResourceDiff{Kind: ChangeAdded, Resource: "Client", ID: nc.ID, Name: nc.Name, Partition: partition})
+		case nc == nil:
+			d.add(ResourceDiff{Kind: ChangeRemoved, Resource: "Client", ID: oc.ID, Name: oc.Name, Partition: partition})
+		default:
+			var ch changes
+			ch.compare("Availability", oc.Availability, nc.Availability)
+			ch.compare("CertificateFingerprint", CertificateFingerprint(oc.Certificate), CertificateFingerprint(nc.Certificate))
+			d.changed(ResourceDiff{Resource: "Client", ID: nc.ID, Name: nc.Name, Partition: partition}, ch)
+		}
+	})
+	diffRecords(o.Peers, n.Peers, func(p *PeerRecord) string { return p.ID }, func(op, np *PeerRecord) {
+		switch {
+		case op == nil:
+			d.add(ResourceDiff{Kind: ChangeAdded, Resource: "Peer", ID: np.ID, Partition: partition})
+		case np == nil:
+			d.add(ResourceDiff{Kind: ChangeRemoved, Resource: "Peer", ID: op.ID, Partition: partition})
+		default:
+			var ch changes
+			ch.compare("Status", op.Status, np.Status)
+			ch.compareSet("Routes", op.Routes, np.Routes)
+			d.changed(ResourceDiff{Resource: "Peer", ID: np.ID, Partition: partition}, ch)
+		}
+	})
+}
+
+func (d *InventoryDiff) add(r ResourceDiff) {
+	d.Resources = append(d.Resources, r)
+}
+
+func (d *InventoryDiff) changed(r ResourceDiff, ch changes) {
+	if len(ch) == 0 {
+		return
+	}
+	r.Kind = ChangeChanged
+	r.Changes = ch
+	d.add(r)
+}
+
+// diffRecords fromとtoをkeyで対応付け、fromの順、続いてtoにだけあるものの順にfを呼ぶ
+//
+// keyが重複する場合は、それぞれの中で何番目に現れたかも併せて対応付ける。
+func diffRecords[T any](from, to []T, key func(*T) string, f func(o, n *T)) {
+	toKeys := occurrenceKeys(to, key)
+	index := make(map[recordKey]*T, len(to))
+	for i := range to {
+		index[toKeys[i]] = &to[i]
+	}
+	fromKeys := occurrenceKeys(from, key)
+	seen := make(map[recordKey]bool, len(from))
+	for i := range from {
+		seen[fromKeys[i]] = true
+		f(&from[i], index[fromKeys[i]])
+	}
+	for i := range to {
+		if !seen[toKeys[i]] {
+			f(nil, &to[i])
+		}
+	}
+}
+
+// recordKey 対応付けのキーと、同じキーの中で何番目に現れたか
+type recordKey struct {
+	key string
+	n   int
+}
+
+func occurrenceKeys[T any](list []T, key func(*T) string) []recordKey {
+	count := map[string]int{}
+	ret := make([]recordKey, len(list))
+	for i := range list {
+		k := key(&list[i])
+		ret[i] = recordKey{key: k, n: count[k]}
+		count[k]++
+	}
+	return ret
+}
+
+type changes []FieldChange
+
+func (ch *changes) compare(field string, o, n any) {
+	if o != n {
+		*ch = append(*ch, FieldChange{Field: field, Old: o, New: n})
+	}
+}
+
+// compareSet 順序を無視して比べる
+func (ch *changes) compareSet(field string, o, n []string) {
+	o, n = sorted(o), sorted(n)
+	if !slices.Equal(o, n) {
+		*ch = append(*ch, FieldChange{Field: field, Old: o, New: n})
+	}
+}
+
+func sorted(s []string) []string {
+	s = slices.Clone(nonNil(s))
+	slices.Sort(s)
+	return s
+}
+
+func (p *PartitionRecord) localRouterID() string {
+	if p.LocalRouter == nil {
+		return ""
+	}
+	return p.LocalRouter.ResourceID
+}
+
+// CertificateFingerprint PEM形式の証明書のSHA-256フィンガープリント("SHA256:"に続く16進数)
+//
+// PEMとして解釈できない場合は文字列全体から求める。空文字列なら空を返す。
+func CertificateFingerprint(cert string) string {
+	if cert == "" {
+		return ""
+	}
+	b := []byte(cert)
+	if block, _ := pem.Decode(b); block != nil {
+		b = block.Bytes
+	}
+	sum := sha256.Sum256(b)
+	return "SHA256:" + hex.EncodeToString(sum[:])
+}
+
+// Empty 差分がなければtrue
+func (d *InventoryDiff) Empty() bool {
+	return len(d.Resources) == 0
+}
+
+// WriteJSON インデント付きのJSONとして書き出す
+func (d *InventoryDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return NewError("InventoryDiff.WriteJSON", err)
+	}
+	return nil
+}
+
+// WriteText 人が読むための形式で書き出す
+//
+// 追加は"+"、削除は"-"、変更は"~"で始まり、変更の場合は続く行に項目ごとの変化を字下げして書く。
+func (d *InventoryDiff) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	for _, r := range d.Resources {
+		mark := map[ChangeKind]string{ChangeAdded: "+", ChangeRemoved: "-", ChangeChanged: "~"}[r.Kind]
+		fmt.Fprintf(&sb, "%s %s %s", mark, r.Resource, r.ID)
+		if r.Name != "" {
+			fmt.Fprintf(&sb, " (%s)", r.Name)
+		}
+		if r.Partition != "" {
+			fmt.Fprintf(&sb, " in partition %s", r.Partition)
+		}
+		sb.WriteByte('\n')
+		for _, c := range r.Changes {
+			fmt.Fprintf(&sb, "    %s: %s -> %s\n", c.Field, formatValue(c.Old), formatValue(c.New))
+		}
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return NewError("InventoryDiff.WriteText", err)
+	}
+	return nil
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCertA = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	testCertB = "-----BEGIN CERTIFICATE-----\nMIIC\n-----END CERTIFICATE-----\n"
+)
+
+func diffBase() *Inventory {
+	return &Inventory{
+		Version: InventoryVersion,
+		Partitions: []PartitionRecord{{
+			ID: "hsm-1", Name: "hsm", Description: "old", Tags: []string{"a", "b"}, Availability: "available",
+			Peers:   []PeerRecord{{ID: "router-1", Status: "UP", Routes: []string{"10.0.0.0/24"}}},
+			Clients: []ClientRecord{{ID: "client-1", Name: "app", Availability: "available", Certificate: testCertA}},
+		}, {
+			ID: "hsm-2", Name: "gone", Tags: []string{}, Availability: "available",
+			Peers:   []PeerRecord{},
+			Clients: []ClientRecord{{ID: "client-2", Name: "old-app", Certificate: testCertA}},
+		}},
+		Licenses: []LicenseRecord{{ID: "license-1", Name: "lic", Tags: []string{}, ServiceClass: "cloud/cloudhsm/license"}},
+	}
+}
+
+func TestDiffInventory(t *testing.T) {
+	assert := require.New(t)
+
+	from := diffBase()
+	to := diffBase()
+	to.Partitions = to.Partitions[:1]
+	to.Partitions[0].Description = "new"
+	to.Partitions[0].Tags = []string{"b", "c"}
+	to.Partitions[0].Peers[0].Status = "DOWN"
+	to.Partitions[0].Clients[0].Certificate = testCertB
+	to.Licenses = append(to.Licenses, LicenseRecord{ID: "license-2", Name: "lic2", Tags: []string{}})
+
+	d := DiffInventory(from, to, DiffOptions{})
+	assert.False(d.Empty())
+	assert.Equal([]ResourceDiff{
+		{Kind: ChangeChanged, Resource: "Partition", ID: "hsm-1", Name: "hsm", Changes: []FieldChange{
+			{Field: "Description", Old: "old", New: "new"},
+			{Field: "Tags", Old: []string{"a", "b"}, New: []string{"b", "c"}},
+		}},
+		{Kind: ChangeChanged, Resource: "Client", ID: "client-1", Name: "app", Partition: "hsm-1", Changes: []FieldChange{
+			{Field: "CertificateFingerprint", Old: CertificateFingerprint(testCertA), New: CertificateFingerprint(testCertB)},
+		}},
+		{Kind: ChangeChanged, Resource: "Peer", ID: "router-1", Partition: "hsm-1", Changes: []FieldChange{
+			{Field: "Status", Old: "UP", New: "DOWN"},
+		}},
+		{Kind: ChangeRemoved, Resource: "Partition", ID: "hsm-2", Name: "gone"},
+		{Kind: ChangeRemoved, Resource: "Client", ID: "client-2", Name: "old-app", Partition: "hsm-2"},
+		{Kind: ChangeAdded, Resource: "License", ID: "license-2", Name: "lic2"},
+	}, d.Resources)
+
+	var text bytes.Buffer
+	assert.NoError(d.WriteText(&text))
+	assert.Contains(text.String(), "~ Partition hsm-1 (hsm)\n    Description: \"old\" -> \"new\"\n    Tags: [a, b] -> [b, c]\n")
+	assert.Contains(text.String(), "- Client client-2 (old-app) in partition hsm-2\n")
+	assert.Contains(text.String(), "+ License license-2 (lic2)\n")
+
+	var buf bytes.Buffer
+	assert.NoError(d.WriteJSON(&buf))
+	var decoded InventoryDiff
+	assert.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(decoded.Resources, len(d.Resources))
+	assert.Equal("DOWN", decoded.Resources[2].Changes[0].New)
+}
+
+func TestDiffInventory_MatchByName(t *testing.T) {
+	assert := require.New(t)
+
+	from := diffBase()
+	to := diffBase()
+	to.Partitions[0].ID = "hsm-10"
+	to.Partitions[0].Clients[0].ID = "client-10"
+	to.Licenses[0].ID = "license-10"
+
+	assert.True(DiffInventory(from, to, DiffOptions{MatchByName: true}).Empty())
+	assert.False(DiffInventory(from, to, DiffOptions{}).Empty())
+	assert.True(DiffInventory(from, diffBase(), DiffOptions{}).Empty())
+}
+
+func TestDiffInventory_DuplicateClientNames(t *testing.T) {
+	assert := require.New(t)
+
+	from := diffBase()
+	from.Partitions[0].Clients = append(from.Partitions[0].Clients,
+		ClientRecord{ID: "client-3", Name: "app", Availability: "available", Certificate: testCertA})
+	to := diffBase()
+	to.Partitions[0].Clients = append(to.Partitions[0].Clients,
+		ClientRecord{ID: "client-3", Name: "app", Availability: "available", Certificate: testCertB})
+
+	// matched by ID, only the second "app" changed
+	d := DiffInventory(from, to, DiffOptions{})
+	assert.Equal([]ResourceDiff{
+		{Kind: ChangeChanged, Resource: "Client", ID: "client-3", Name: "app", Partition: "hsm-1", Changes: []FieldChange{
+			{Field: "CertificateFingerprint", Old: CertificateFingerprint(testCertA), New: CertificateFingerprint(testCertB)},
+		}},
+	}, d.Resources)
+
+	// matched by name, clients with the same name are paired in order
+	to.Partitions[0].Clients[0].ID = "client-10"
+	to.Partitions[0].Clients[1].ID = "client-30"
+	d = DiffInventory(from, to, DiffOptions{MatchByName: true})
+	assert.Len(d.Resources, 1)
+	assert.Equal("client-30", d.Resources[0].ID)
+	assert.Equal(ChangeChanged, d.Resources[0].Kind)
+
+	// a removed duplicate is reported rather than collapsed into the remaining one
+	to = diffBase()
+	d = DiffInventory(from, to, DiffOptions{MatchByName: true})
+	assert.Equal([]ResourceDiff{
+		{Kind: ChangeRemoved, Resource: "Client", ID: "client-3", Name: "app", Partition: "hsm-1"},
+	}, d.Resources)
+}
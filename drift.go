@@ -0,0 +1,361 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// Severity Findingの重大度
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+var severityNames = []string{"info", "warning", "critical"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// MarshalText "info"、"warning"、"critical"のいずれかとして書き出す
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText "info"、"warning"、"critical"のいずれかを読み込む
+func (s *Severity) UnmarshalText(b []byte) error {
+	for i, name := range severityNames {
+		if strings.EqualFold(string(b), name) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", string(b))
+}
+
+// DriftRule Findingの種類
+type DriftRule string
+
+const (
+	DriftPartitionAdded        DriftRule = "partition-added"
+	DriftPartitionDeleted      DriftRule = "partition-deleted"
+	DriftPartitionRenamed      DriftRule = "partition-renamed"
+	DriftPartitionAvailability DriftRule = "partition-availability"
+	DriftPartitionModified     DriftRule = "partition-modified"
+	DriftUnknownCertificate    DriftRule = "unknown-client-certificate"
+	DriftClientAdded           DriftRule = "client-added"
+	DriftClientDeleted         DriftRule = "client-deleted"
+	DriftClientModified        DriftRule = "client-modified"
+	DriftPeerAdded             DriftRule = "peer-added"
+	DriftPeerDeleted           DriftRule = "peer-deleted"
+	DriftPeerDown              DriftRule = "peer-down"
+	DriftPeerModified          DriftRule = "peer-modified"
+	DriftLicenseAdded          DriftRule = "license-added"
+	DriftLicenseDeleted        DriftRule = "license-deleted"
+	DriftLicenseModified       DriftRule = "license-modified"
+)
+
+// DefaultDriftSeverity 各DriftRuleの既定の重大度
+var DefaultDriftSeverity = map[DriftRule]Severity{
+	DriftPartitionAdded:        SeverityWarning,
+	DriftPartitionDeleted:      SeverityCritical,
+	DriftPartitionRenamed:      SeverityWarning,
+	DriftPartitionAvailability: SeverityWarning,
+	DriftPartitionModified:     SeverityInfo,
+	DriftUnknownCertificate:    SeverityCritical,
+	DriftClientAdded:           SeverityWarning,
+	DriftClientDeleted:         SeverityWarning,
+	DriftClientModified:        SeverityInfo,
+	DriftPeerAdded:             SeverityWarning,
+	DriftPeerDeleted:           SeverityWarning,
+	DriftPeerDown:              SeverityCritical,
+	DriftPeerModified:          SeverityWarning,
+	DriftLicenseAdded:          SeverityInfo,
+	DriftLicenseDeleted:        SeverityCritical,
+	DriftLicenseModified:       SeverityInfo,
+}
+
+// Finding 基準からの逸脱1件
+type Finding struct {
+	Severity Severity  `json:"severity"`
+	Rule     DriftRule `json:"rule"`
+
+	// Resource "Partition"、"Client"、"Peer"、"License"のいずれか
+	Resource  string `json:"resource"`
+	ID        string `json:"id"`
+	Partition string `json:"partition,omitempty"`
+	Message   string `json:"message"`
+}
+
+// DriftOptions CheckDriftの動作を指定する
+type DriftOptions struct {
+	// MatchByName パーティションとライセンスをIDではなく名前で対応付ける
+	MatchByName bool
+
+	// Severity DriftRuleごとの重大度をDefaultDriftSeverityから変える
+	Severity map[DriftRule]Severity
+}
+
+// DriftReport CheckDriftの結果
+type DriftReport struct {
+	// BaselineTakenAt 基準としたInventoryの取得時刻
+	BaselineTakenAt time.Time `json:"baseline_taken_at"`
+
+	// CheckedAt 現在の状態を取得した時刻
+	CheckedAt time.Time `json:"checked_at"`
+
+	Findings []Finding `json:"findings"`
+}
+
+// ErrDriftDetected 基準から逸脱していることを表す
+var ErrDriftDetected = errors.New("drift detected")
+
+// DriftError 閾値以上の重大度のFinding
+type DriftError struct {
+	Threshold Severity
+	Findings  []Finding
+}
+
+func (e *DriftError) Error() string {
+	return fmt.Sprintf("drift detected: %d findings at or above %s", len(e.Findings), e.Threshold)
+}
+
+func (e *DriftError) Is(target error) bool {
+	return target == ErrDriftDetected
+}
+
+// LoadBaseline WriteJSONまたはWriteYAMLで書き出したInventoryをファイルから読み込む
+func LoadBaseline(path string) (*Inventory, error) {
+	f, err := os.Open(path) //nolint:gosec // the path is chosen by the caller
+	if err != nil {
+		return nil, NewError("LoadBaseline", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return LoadInventory(f)
+}
+
+// CheckDrift 現在の状態を取得し、基準のInventoryと比べる
+func CheckDrift(ctx context.Context, client *v1.Client, baseline *Inventory, opts DriftOptions) (*DriftReport, error) {
+	live, err := Snapshot(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateDrift(baseline, live, opts), nil
+}
+
+// EvaluateDrift 取得済みの2つのInventoryを比べる
+//
+// 基準のどのクライアントとも異なる証明書が登録されていればDriftUnknownCertificate、
+// 状態がDOWNのピアがあれば基準での状態によらずDriftPeerDownとする。
+func EvaluateDrift(baseline, live *Inventory, opts DriftOptions) *DriftReport {
+	e := &driftEvaluator{
+		opts:   opts,
+		known:  map[string]bool{},
+		report: &DriftReport{BaselineTakenAt: baseline.TakenAt, CheckedAt: live.TakenAt, Findings: []Finding{}},
+		certs:  map[string]string{},
+	}
+	for _, p := range baseline.Partitions {
+		for _, c := range p.Clients {
+			e.known[CertificateFingerprint(c.Certificate)] = true
+		}
+	}
+	for _, p := range live.Partitions {
+		for _, c := range p.Clients {
+			e.certs[p.ID+"/"+c.ID] = c.Certificate
+		}
+	}
+
+	for _, r := range DiffInventory(baseline, live, DiffOptions{MatchByName: opts.MatchByName}).Resources {
+		e.evaluate(&r)
+	}
+
+	for _, p := range live.Partitions {
+		for _, peer := range p.Peers {
+			if peer.Status == string(v1.CloudHSMPeerStatusDOWN) {
+				e.add(DriftPeerDown, "Peer", peer.ID, p.ID, "peer is DOWN")
+			}
+		}
+	}
+	return e.report
+}
+
+type driftEvaluator struct {
+	opts   DriftOptions
+	known  map[string]bool
+	certs  map[string]string
+	report *DriftReport
+}
+
+func (e *driftEvaluator) evaluate(r *ResourceDiff) {
+	switch r.Resource {
+	case "Partition":
+		switch r.Kind {
+		case ChangeAdded:
+			e.add(DriftPartitionAdded, r.Resource, r.ID, "", "partition added")
+		case ChangeRemoved:
+			e.add(DriftPartitionDeleted, r.Resource, r.ID, "", "partition deleted")
+		case ChangeChanged:
+			for _, c := range r.Changes {
+				switch c.Field {
+				case "Name":
+					e.add(DriftPartitionRenamed, r.Resource, r.ID, "", fmt.Sprintf("partition renamed from %q to %q", c.Old, c.New))
+				case "Availability":
+					e.add(DriftPartitionAvailability, r.Resource, r.ID, "", fmt.Sprintf("availability changed from %s to %s", c.Old, c.New))
+				default:
+					e.add(DriftPartitionModified, r.Resource, r.ID, "", changeMessage(&c))
+				}
+			}
+		}
+
+	case "Client":
+		switch r.Kind {
+		case ChangeAdded:
+			e.clientCertificate(r, DriftClientAdded, "client added")
+		case ChangeRemoved:
+			e.add(DriftClientDeleted, r.Resource, r.ID, r.Partition, "client deleted")
+		case ChangeChanged:
+			for _, c := range r.Changes {
+				if c.Field == "CertificateFingerprint" {
+					e.clientCertificate(r, DriftClientModified, "client certificate replaced")
+				} else {
+					e.add(DriftClientModified, r.Resource, r.ID, r.Partition, changeMessage(&c))
+				}
+			}
+		}
+
+	case "Peer":
+		switch r.Kind {
+		case ChangeAdded:
+			e.add(DriftPeerAdded, r.Resource, r.ID, r.Partition, "peer added")
+		case ChangeRemoved:
+			e.add(DriftPeerDeleted, r.Resource, r.ID, r.Partition, "peer deleted")
+		case ChangeChanged:
+			for _, c := range r.Changes {
+				// DOWNになったピアはDriftPeerDownで報告する
+				if c.Field == "Status" && c.New == string(v1.CloudHSMPeerStatusDOWN) {
+					continue
+				}
+				e.add(DriftPeerModified, r.Resource, r.ID, r.Partition, changeMessage(&c))
+			}
+		}
+
+	case "License":
+		switch r.Kind {
+		case ChangeAdded:
+			e.add(DriftLicenseAdded, r.Resource, r.ID, "", "license added")
+		case ChangeRemoved:
+			e.add(DriftLicenseDeleted, r.Resource, r.ID, "", "license deleted")
+		case ChangeChanged:
+			for _, c := range r.Changes {
+				e.add(DriftLicenseModified, r.Resource, r.ID, "", changeMessage(&c))
+			}
+		}
+	}
+}
+
+// clientCertificate 基準にない証明書ならDriftUnknownCertificate、そうでなければruleとして報告する
+func (e *driftEvaluator) clientCertificate(r *ResourceDiff, rule DriftRule, msg string) {
+	fp := CertificateFingerprint(e.certs[r.Partition+"/"+r.ID])
+	if !e.known[fp] {
+		e.add(DriftUnknownCertificate, r.Resource, r.ID, r.Partition, fmt.Sprintf("%s with unknown certificate %s", msg, fp))
+		return
+	}
+	e.add(rule, r.Resource, r.ID, r.Partition, msg)
+}
+
+func (e *driftEvaluator) add(rule DriftRule, resource, id, partition, msg string) {
+	sev, ok := e.opts.Severity[rule]
+	if !ok {
+		sev = DefaultDriftSeverity[rule]
+	}
+	e.report.Findings = append(e.report.Findings, Finding{
+		Severity:  sev,
+		Rule:      rule,
+		Resource:  resource,
+		ID:        id,
+		Partition: partition,
+		Message:   msg,
+	})
+}
+
+func changeMessage(c *FieldChange) string {
+	return fmt.Sprintf("%s changed from %s to %s", c.Field, formatValue(c.Old), formatValue(c.New))
+}
+
+// Max 最も高い重大度。Findingがなければ-1
+func (r *DriftReport) Max() Severity {
+	ret := Severity(-1)
+	for _, f := range r.Findings {
+		ret = max(ret, f.Severity)
+	}
+	return ret
+}
+
+// Err threshold以上の重大度のFindingがあれば*DriftErrorを返す
+func (r *DriftReport) Err(threshold Severity) error {
+	var found []Finding
+	for _, f := range r.Findings {
+		if f.Severity >= threshold {
+			found = append(found, f)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	return &DriftError{Threshold: threshold, Findings: found}
+}
+
+// WriteJSON インデント付きのJSONとして書き出す
+func (r *DriftReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return NewError("DriftReport.WriteJSON", err)
+	}
+	return nil
+}
+
+// WriteText Findingを1行ずつ書き出す
+func (r *DriftReport) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	for _, f := range r.Findings {
+		fmt.Fprintf(&sb, "%-8s %s %s %s", strings.ToUpper(f.Severity.String()), f.Rule, f.Resource, f.ID)
+		if f.Partition != "" {
+			fmt.Fprintf(&sb, " in partition %s", f.Partition)
+		}
+		fmt.Fprintf(&sb, ": %s\n", f.Message)
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return NewError("DriftReport.WriteText", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateDrift(t *testing.T) {
+	assert := require.New(t)
+
+	baseline := diffBase()
+	live := diffBase()
+	live.Partitions = live.Partitions[:1]
+	live.Partitions[0].Name = "renamed"
+	live.Partitions[0].Peers[0].Status = "DOWN"
+	live.Partitions[0].Clients = append(live.Partitions[0].Clients,
+		ClientRecord{ID: "client-3", Name: "intruder", Certificate: testCertB},
+		ClientRecord{ID: "client-4", Name: "copy", Certificate: testCertA},
+	)
+	live.Licenses = nil
+
+	report := EvaluateDrift(baseline, live, DriftOptions{
+		Severity: map[DriftRule]Severity{DriftClientAdded: SeverityInfo},
+	})
+
+	type summary struct {
+		Severity Severity
+		Rule     DriftRule
+		ID       string
+	}
+	var got []summary
+	for _, f := range report.Findings {
+		got = append(got, summary{f.Severity, f.Rule, f.ID})
+	}
+	assert.Equal([]summary{
+		{SeverityWarning, DriftPartitionRenamed, "hsm-1"},
+		{SeverityCritical, DriftUnknownCertificate, "client-3"},
+		{SeverityInfo, DriftClientAdded, "client-4"},
+		{SeverityCritical, DriftPartitionDeleted, "hsm-2"},
+		{SeverityWarning, DriftClientDeleted, "client-2"},
+		{SeverityCritical, DriftLicenseDeleted, "license-1"},
+		{SeverityCritical, DriftPeerDown, "router-1"},
+	}, got)
+	assert.Equal(SeverityCritical, report.Max())
+
+	err := report.Err(SeverityCritical)
+	assert.True(errors.Is(err, ErrDriftDetected))
+	var derr *DriftError
+	assert.True(errors.As(err, &derr))
+	assert.Len(derr.Findings, 4)
+
+	var text bytes.Buffer
+	assert.NoError(report.WriteText(&text))
+	assert.Contains(text.String(), `WARNING  partition-renamed Partition hsm-1: partition renamed from "hsm" to "renamed"`)
+
+	assert.NoError(EvaluateDrift(baseline, diffBase(), DriftOptions{}).Err(SeverityInfo))
+}
+
+func TestCheckDrift(t *testing.T) {
+	assert := require.New(t)
+	client := newTestClientWithHandler(t, inventoryHandler(t))
+	ctx := context.Background()
+
+	inv, err := Snapshot(ctx, client)
+	assert.NoError(err)
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	var buf bytes.Buffer
+	assert.NoError(inv.WriteYAML(&buf))
+	assert.NoError(os.WriteFile(path, buf.Bytes(), 0o600))
+
+	baseline, err := LoadBaseline(path)
+	assert.NoError(err)
+	report, err := CheckDrift(ctx, client, baseline, DriftOptions{})
+	assert.NoError(err)
+	assert.Empty(report.Findings)
+	assert.Equal(Severity(-1), report.Max())
+
+	baseline.Partitions[0].Clients = nil
+	report, err = CheckDrift(ctx, client, baseline, DriftOptions{})
+	assert.NoError(err)
+	assert.Len(report.Findings, 1)
+	assert.Equal(DriftUnknownCertificate, report.Findings[0].Rule)
+}
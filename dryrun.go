@@ -0,0 +1,106 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+
+	ht "github.com/ogen-go/ogen/http"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// WithDryRun 変更操作を実際には送らず、送るはずだったリクエストを記録するだけにする
+//
+// List/Readは通常どおり行う。Create/Update/Deleteはリクエストボディ(証明書やSecretKeyは伏せる)を
+// WithLoggerで指定したロガー(なければslog.Default())にInfoで記録し、成功したものとして扱う。
+// CreateとUpdateの戻り値は送るはずだったリクエストの内容になる。
+func WithDryRun() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.dryRun = true
+	}
+}
+
+// dryRunStatus 変更操作ごとに返す成功時のステータスコード
+var dryRunStatus = map[v1.OperationName]int{
+	v1.CloudhsmCloudhsmsCreateOperation:         http.StatusCreated,
+	v1.CloudhsmCloudhsmsUpdateOperation:         http.StatusOK,
+	v1.CloudhsmCloudhsmsDestroyOperation:        http.StatusNoContent,
+	v1.CloudhsmCloudhsmsClientsCreateOperation:  http.StatusCreated,
+	v1.CloudhsmCloudhsmsClientsUpdateOperation:  http.StatusOK,
+	v1.CloudhsmCloudhsmsClientsDestroyOperation: http.StatusNoContent,
+	v1.CloudhsmCloudhsmsPeersCreateOperation:    http.StatusNoContent,
+	v1.CloudhsmCloudhsmsPeersDestroyOperation:   http.StatusNoContent,
+	v1.CloudhsmLicensesCreateOperation:          http.StatusCreated,
+	v1.CloudhsmLicensesUpdateOperation:          http.StatusOK,
+	v1.CloudhsmLicensesDestroyOperation:         http.StatusNoContent,
+}
+
+// dryRunDoer 変更操作のリクエストを記録し、リクエストボディをそのまま返す
+type dryRunDoer struct {
+	next   ht.Client
+	logger *slog.Logger
+}
+
+func (d *dryRunDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return d.next.Do(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	ctx := req.Context()
+	attrs := []any{
+		slog.String("http_method", req.Method),
+		slog.String("url", req.URL.String()),
+	}
+	status := http.StatusOK
+	if c, ok := CallFromContext(ctx); ok {
+		attrs = append(attrs, slog.Any("call", c))
+		if st, ok := dryRunStatus[c.Operation]; ok {
+			status = st
+		}
+	}
+	if len(body) > 0 {
+		attrs = append(attrs, slog.String("body", Redact(string(body))))
+	}
+	d.logger.InfoContext(ctx, "cloudhsm: dry run", attrs...)
+
+	resp := &http.Response{
+		Status:     http.StatusText(status),
+		StatusCode: status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+	if status != http.StatusNoContent {
+		resp.Header.Set("Content-Type", "application/json")
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+	}
+	return resp, nil
+}
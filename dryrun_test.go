@@ -0,0 +1,84 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	assert := require.New(t)
+	l, buf := newTestLogger()
+	var mutations int
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			mutations++
+		}
+		respondJSON(w, http.StatusOK, TemplateWrappedCloudHSM)
+	}), WithDryRun(), WithLogger(l))
+	ctx := context.Background()
+
+	hsm, err := NewCloudHSMOp(client).Read(ctx, "hsm-1")
+	assert.NoError(err)
+	assert.Equal(TemplateCloudHSM.Name, hsm.Name)
+
+	created, err := NewCloudHSMOp(client).Create(ctx, CloudHSMCreateParams{
+		Name:               "new-partition",
+		Ipv4NetworkAddress: netip.MustParsePrefix("192.168.0.0/28"),
+	})
+	assert.NoError(err)
+	assert.Equal("new-partition", created.Name)
+
+	updated, err := NewLicenseOp(client).Update(ctx, "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "renamed"})
+	assert.NoError(err)
+	assert.Equal("renamed", updated.Name)
+
+	clients, err := NewClientOp(client, hsm)
+	assert.NoError(err)
+	_, err = clients.Create(ctx, CloudHSMClientCreateParams{
+		Name:        "app",
+		Certificate: "-----BEGIN CERTIFICATE-----\nMIIBsecret\n-----END CERTIFICATE-----",
+	})
+	assert.NoError(err)
+
+	peers, err := NewPeerOp(client, hsm)
+	assert.NoError(err)
+	assert.NoError(peers.Create(ctx, CloudHSMPeerCreateParams{RouterID: "router-1", SecretKey: "pairing-secret"}))
+	assert.NoError(peers.Delete(ctx, "router-1"))
+	assert.NoError(NewCloudHSMOp(client).Delete(ctx, "hsm-1"))
+
+	assert.Equal(0, mutations)
+
+	var dryRuns []map[string]any
+	for _, rec := range logRecords(t, buf) {
+		if rec["msg"] == "cloudhsm: dry run" {
+			dryRuns = append(dryRuns, rec)
+		}
+	}
+	assert.Len(dryRuns, 6)
+	assert.Equal(http.MethodPost, dryRuns[0]["http_method"])
+	assert.Contains(dryRuns[0]["body"], "new-partition")
+	assert.Equal("License.Update", dryRuns[1]["call"].(map[string]any)["method"])
+	assert.NotContains(buf.String(), "MIIBsecret")
+	assert.NotContains(buf.String(), "pairing-secret")
+	assert.Equal(http.MethodDelete, dryRuns[5]["http_method"])
+	assert.NotContains(dryRuns[5], "body")
+}
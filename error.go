@@ -14,7 +14,36 @@
 
 package cloudhsm
 
-import "github.com/sacloud/saclient-go"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/saclient-go"
+)
+
+// ErrEmptyResponse 成功したレスポンスに期待するオブジェクトが含まれていないことを表す
+var ErrEmptyResponse = errors.New("empty response")
+
+// UnconfirmedCreateError 作成は成功したが、レスポンスに作成したリソースが含まれていなかったことを表す
+//
+// リソースは作成されている可能性があるため、再試行する前にNameやTagsで一覧から探すこと。
+// errors.Is(err, ErrEmptyResponse)も成り立つ。
+type UnconfirmedCreateError struct {
+	// Resource "License"など
+	Resource string
+
+	// Name、Tags 作成を依頼したリソースの名前とタグ
+	Name string
+	Tags []string
+}
+
+func (e *UnconfirmedCreateError) Error() string {
+	return fmt.Sprintf("%s %q may have been created, but the response did not contain it", e.Resource, e.Name)
+}
+
+func (e *UnconfirmedCreateError) Is(target error) bool {
+	return target == ErrEmptyResponse
+}
 
 type Error struct {
 	msg string
@@ -44,3 +73,12 @@ func NewError(msg string, err error) *Error {
 func NewAPIError(method string, code int, err error) *Error {
 	return &Error{msg: method, err: saclient.NewError(code, "", err)}
 }
+
+// relabel errがこのパッケージの*Errorなら、前置きを重ねずに操作名だけをmsgに付け替える
+func relabel(msg string, err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return &Error{msg: msg, err: e.err}
+	}
+	return NewError(msg, err)
+}
@@ -73,6 +73,34 @@ func newTestClient(v any, s ...int) *v1.Client {
 	return c
 }
 
+// newTestClientWithHandler is like newTestClient but lets the test drive the
+// server with an arbitrary handler and pass client options.
+func newTestClientWithHandler(t *testing.T, h http.Handler, opts ...ClientOption) *v1.Client {
+	sv := httptest.NewServer(h)
+	t.Cleanup(sv.Close)
+	api, err := theClient.DupWith(saclient.WithTestServer(sv))
+	require.NoError(t, err)
+	c, err := NewClientWithApiUrl(sv.URL, api, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+// respondJSON writes v as a JSON response with the given status.
+func respondJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if status == http.StatusNoContent {
+		return
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	if _, err = w.Write(j); err != nil {
+		panic(err)
+	}
+}
+
 func newIntegratedClient(t *testing.T) *v1.Client {
 	testutil.PreCheckEnvsFunc(
 		"SAKURA_ACCESS_TOKEN",
@@ -141,6 +169,8 @@ var TemplateCloudHSM = func() v1.CloudHSM {
 	return ret
 }()
 
+var TemplatePartition = PartitionFromCloudHSM(&TemplateCloudHSM)
+
 var TemplateCreateCloudHSM = func() v1.CreateCloudHSM {
 	var ret v1.CreateCloudHSM
 	ret.SetFake()
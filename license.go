@@ -17,6 +17,7 @@ package cloudhsm
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/go-faster/errors"
 	ogen "github.com/ogen-go/ogen/validate"
@@ -24,29 +25,58 @@ import (
 )
 
 type LicenseAPI interface {
-	List(ctx context.Context) ([]v1.CloudHSMSoftwareLicense, error)
-	Create(ctx context.Context, request CloudHSMSoftwareLicenseCreateParams) (*v1.CreateCloudHSMSoftwareLicense, error)
-	Read(ctx context.Context, id string) (*v1.CloudHSMSoftwareLicense, error)
-	Update(ctx context.Context, id string, params CloudHSMSoftwareLicenseUpdateParams) (*v1.CloudHSMSoftwareLicense, error)
-	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]License, error)
+	Create(ctx context.Context, request CloudHSMSoftwareLicenseCreateParams) (*License, error)
+	Read(ctx context.Context, id LicenseID) (*License, error)
+	Update(ctx context.Context, id LicenseID, params CloudHSMSoftwareLicenseUpdateParams) (*License, error)
+	Delete(ctx context.Context, id LicenseID, opts ...DeleteOption) error
+	CreateBatch(ctx context.Context, p LicenseBatchParams, opts BulkOptions) (*BulkResult[License, LicenseID], error)
+	DeleteByTag(ctx context.Context, tag string, opts BulkOptions, dopts ...DeleteOption) (*BulkResult[License, LicenseID], error)
 }
 
 var _ LicenseAPI = (*LicenseOp)(nil)
 
 type LicenseOp struct {
 	client *v1.Client
+	s      *settings
 }
 
 func NewLicenseOp(client *v1.Client) LicenseAPI {
-	return &LicenseOp{client: client}
+	op := &LicenseOp{client: client, s: settingsOf(client)}
+	if c := op.s.cache; c != nil {
+		return &cachedLicenseOp{next: op, cache: c}
+	}
+	return op
+}
+
+func (op *LicenseOp) newCall(method string, name v1.OperationName, id LicenseID) *Call {
+	return &Call{Operation: name, Method: method, LicenseID: string(id)}
 }
 
-func (op *LicenseOp) List(ctx context.Context) ([]v1.CloudHSMSoftwareLicense, error) {
-	resp, err := op.client.CloudhsmLicensesList(ctx)
+// before 監査記録に残す操作前の状態。CloudHSMOp.beforeと同じく生成コードを直接呼ぶ
+func (op *LicenseOp) before(id LicenseID) func(context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		resp, err := op.client.CloudhsmLicensesRetrieve(ctx, v1.CloudhsmLicensesRetrieveParams{ResourceID: string(id)})
+		if err != nil {
+			return nil, err
+		}
+		ret, ok := resp.GetLicense().Get()
+		if !ok {
+			return nil, ErrEmptyResponse
+		}
+		lic := LicenseFromCloudHSMSoftwareLicense(&ret)
+		return &lic, nil
+	}
+}
+
+func (op *LicenseOp) List(ctx context.Context) ([]License, error) {
+	resp, err := call(ctx, op.s, op.newCall("License.List", v1.CloudhsmLicensesListOperation, ""), func(ctx context.Context) (*v1.PaginatedCloudHSMSoftwareLicenseList, error) {
+		return op.client.CloudhsmLicensesList(ctx)
+	})
 	if err != nil {
 		return nil, NewAPIError("License.List", 0, err)
 	}
-	return resp.Licenses, nil
+	return convertAll(resp.Licenses, LicenseFromCloudHSMSoftwareLicense), nil
 }
 
 type CloudHSMSoftwareLicenseCreateParams struct {
@@ -55,28 +85,49 @@ type CloudHSMSoftwareLicenseCreateParams struct {
 	Tags        []string
 }
 
-func (op *LicenseOp) Create(ctx context.Context, p CloudHSMSoftwareLicenseCreateParams) (*v1.CreateCloudHSMSoftwareLicense, error) {
+// Create ライセンスを作成する
+//
+// レスポンスにライセンスが含まれていなければ*UnconfirmedCreateErrorを返す。この場合もライセンスは
+// 作成されている可能性があるため、再試行すると重複しうる。
+func (op *LicenseOp) Create(ctx context.Context, p CloudHSMSoftwareLicenseCreateParams) (*License, error) {
 	if p.Tags == nil {
 		p.Tags = []string{}
 	}
-	resp, err := op.client.CloudhsmLicensesCreate(
-		ctx,
-		&v1.WrappedCreateCloudHSMSoftwareLicense{
-			License: v1.NewOptCreateCloudHSMSoftwareLicense(v1.CreateCloudHSMSoftwareLicense{
-				ServiceClass: v1.CloudHSMSoftwareLicenseServiceClassEnumCloudCloudhsmLicenseL7,
-				Name:         p.Name,
-				Description:  intoOpt[v1.OptString](p.Description),
-				Tags:         p.Tags,
-			}),
-		},
-	)
+	c := op.newCall("License.Create", v1.CloudhsmLicensesCreateOperation, "")
+	resp, err := mutate(ctx, op.s, c, nil, func(ctx context.Context) (*v1.WrappedCreateCloudHSMSoftwareLicense, error) {
+		resp, err := op.client.CloudhsmLicensesCreate(
+			ctx,
+			&v1.WrappedCreateCloudHSMSoftwareLicense{
+				License: v1.NewOptCreateCloudHSMSoftwareLicense(v1.CreateCloudHSMSoftwareLicense{
+					ServiceClass: v1.CloudHSMSoftwareLicenseServiceClassEnumCloudCloudhsmLicenseL7,
+					Name:         p.Name,
+					Description:  intoOpt[v1.OptString](p.Description),
+					Tags:         p.Tags,
+				}),
+			},
+		)
+		if err == nil {
+			if ret, ok := resp.GetLicense().Get(); ok {
+				c.recordCreated(&c.LicenseID, ret.GetID())
+			}
+		}
+		return resp, err
+	}, func(_ *Call, resp *v1.WrappedCreateCloudHSMSoftwareLicense) any {
+		ret, ok := resp.GetLicense().Get()
+		if !ok {
+			return nil
+		}
+		lic := LicenseFromCreateCloudHSMSoftwareLicense(&ret)
+		return &lic
+	})
 
 	if err == nil {
 		ret, ok := resp.GetLicense().Get()
 		if !ok {
-			return nil, nil
+			return nil, NewAPIError("License.Create", 0, &UnconfirmedCreateError{Resource: "License", Name: p.Name, Tags: slices.Clone(p.Tags)})
 		}
-		return &ret, nil
+		lic := LicenseFromCreateCloudHSMSoftwareLicense(&ret)
+		return &lic, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("License.Create", 0, err)
 	} else if e.StatusCode == http.StatusUnprocessableEntity {
@@ -86,20 +137,24 @@ func (op *LicenseOp) Create(ctx context.Context, p CloudHSMSoftwareLicenseCreate
 	}
 }
 
-func (op *LicenseOp) Read(ctx context.Context, id string) (*v1.CloudHSMSoftwareLicense, error) {
-	resp, err := op.client.CloudhsmLicensesRetrieve(
-		ctx,
-		v1.CloudhsmLicensesRetrieveParams{
-			ResourceID: id,
-		},
-	)
+// Read ライセンスを取得する。レスポンスにライセンスが含まれていなければErrEmptyResponseを返す
+func (op *LicenseOp) Read(ctx context.Context, id LicenseID) (*License, error) {
+	resp, err := call(ctx, op.s, op.newCall("License.Read", v1.CloudhsmLicensesRetrieveOperation, id), func(ctx context.Context) (*v1.WrappedCloudHSMSoftwareLicense, error) {
+		return op.client.CloudhsmLicensesRetrieve(
+			ctx,
+			v1.CloudhsmLicensesRetrieveParams{
+				ResourceID: string(id),
+			},
+		)
+	})
 
 	if err == nil {
 		ret, ok := resp.GetLicense().Get()
 		if !ok {
-			return nil, nil
+			return nil, NewAPIError("License.Read", 0, ErrEmptyResponse)
 		}
-		return &ret, nil
+		lic := LicenseFromCloudHSMSoftwareLicense(&ret)
+		return &lic, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("License.Read", 0, err)
 	} else if e.StatusCode == http.StatusNotFound {
@@ -110,37 +165,54 @@ func (op *LicenseOp) Read(ctx context.Context, id string) (*v1.CloudHSMSoftwareL
 }
 
 type CloudHSMSoftwareLicenseUpdateParams struct {
-	Name        string
-	Description string
+	Name string
+	// Description APIでは省略できないため、nilなら空文字列として送る
+	Description *string
 	Tags        []string
 }
 
-func (op *LicenseOp) Update(ctx context.Context, id string, p CloudHSMSoftwareLicenseUpdateParams) (*v1.CloudHSMSoftwareLicense, error) {
+// Update ライセンスを更新する。レスポンスにライセンスが含まれていなければ改めてReadで取得する
+func (op *LicenseOp) Update(ctx context.Context, id LicenseID, p CloudHSMSoftwareLicenseUpdateParams) (*License, error) {
 	if p.Tags == nil {
 		p.Tags = []string{}
 	}
 
-	resp, err := op.client.CloudhsmLicensesUpdate(
-		ctx,
-		&v1.WrappedCloudHSMSoftwareLicense{
-			License: v1.NewOptCloudHSMSoftwareLicense(v1.CloudHSMSoftwareLicense{
-				ServiceClass: v1.CloudHSMSoftwareLicenseServiceClassEnumCloudCloudhsmLicenseL7,
-				Name:         p.Name,
-				Description:  p.Description,
-				Tags:         p.Tags,
-			}),
-		},
-		v1.CloudhsmLicensesUpdateParams{
-			ResourceID: id,
-		},
-	)
+	resp, err := mutate(ctx, op.s, op.newCall("License.Update", v1.CloudhsmLicensesUpdateOperation, id), op.before(id), func(ctx context.Context) (*v1.WrappedCloudHSMSoftwareLicense, error) {
+		return op.client.CloudhsmLicensesUpdate(
+			ctx,
+			&v1.WrappedCloudHSMSoftwareLicense{
+				License: v1.NewOptCloudHSMSoftwareLicense(v1.CloudHSMSoftwareLicense{
+					ServiceClass: v1.CloudHSMSoftwareLicenseServiceClassEnumCloudCloudhsmLicenseL7,
+					Name:         p.Name,
+					Description:  deref(p.Description),
+					Tags:         p.Tags,
+				}),
+			},
+			v1.CloudhsmLicensesUpdateParams{
+				ResourceID: string(id),
+			},
+		)
+	}, func(_ *Call, resp *v1.WrappedCloudHSMSoftwareLicense) any {
+		ret, ok := resp.GetLicense().Get()
+		if !ok {
+			return nil
+		}
+		lic := LicenseFromCloudHSMSoftwareLicense(&ret)
+		return &lic
+	})
 
 	if err == nil {
 		ret, ok := resp.GetLicense().Get()
 		if !ok {
-			return nil, nil
+			// 更新は成功しているため、改めて取得する。失敗はUpdateのエラーとして返す
+			lic, err := op.Read(ctx, id)
+			if err != nil {
+				return nil, relabel("License.Update", err)
+			}
+			return lic, nil
 		}
-		return &ret, nil
+		lic := LicenseFromCloudHSMSoftwareLicense(&ret)
+		return &lic, nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("License.Update", 0, err)
 	} else if e.StatusCode == http.StatusUnprocessableEntity {
@@ -150,13 +222,19 @@ func (op *LicenseOp) Update(ctx context.Context, id string, p CloudHSMSoftwareLi
 	}
 }
 
-func (op *LicenseOp) Delete(ctx context.Context, id string) error {
-	err := op.client.CloudhsmLicensesDestroy(
-		ctx,
-		v1.CloudhsmLicensesDestroyParams{
-			ResourceID: id,
-		},
-	)
+func (op *LicenseOp) Delete(ctx context.Context, id LicenseID, opts ...DeleteOption) error {
+	if err := op.protect(ctx, id, opts); err != nil {
+		return err
+	}
+
+	err := op.s.mutate(ctx, op.newCall("License.Delete", v1.CloudhsmLicensesDestroyOperation, id), op.before(id), func(ctx context.Context) error {
+		return op.client.CloudhsmLicensesDestroy(
+			ctx,
+			v1.CloudhsmLicensesDestroyParams{
+				ResourceID: string(id),
+			},
+		)
+	}, nil)
 
 	if err == nil {
 		return nil
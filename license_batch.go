@@ -0,0 +1,139 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// LicenseBatchParams LicenseOp.CreateBatchの引数
+type LicenseBatchParams struct {
+	// Count 作成する数。Itemsが空でなければ無視する
+	Count int
+
+	// Items 1件ごとの値。空でなければその数だけ作成し、NameTemplateから.Itemとして参照できる。
+	// 空のスライスはnilと同じくCountに従う
+	Items []string
+
+	// NameTemplate 名前を作るtext/templateのテンプレート。.Index(0から始まる)と.Itemを参照できる。
+	// 例: "app-{{.Index}}"
+	NameTemplate string
+
+	// Description、Tags すべてのライセンスに共通の説明とタグ
+	Description *string
+	Tags        []string
+}
+
+// LicenseNameData NameTemplateに渡す値
+type LicenseNameData struct {
+	Index int
+	Item  string
+}
+
+// names NameTemplateから名前を作る。同じ名前ができた場合はエラーを返す
+func (p *LicenseBatchParams) names() ([]string, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(p.NameTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	n := p.Count
+	if len(p.Items) > 0 {
+		n = len(p.Items)
+	}
+	ret := make([]string, 0, n)
+	for i := range n {
+		data := LicenseNameData{Index: i}
+		if len(p.Items) > 0 {
+			data.Item = p.Items[i]
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, err
+		}
+		name := sb.String()
+		if name == "" {
+			return nil, fmt.Errorf("empty name for index %d", i)
+		} else if slices.Contains(ret, name) {
+			return nil, fmt.Errorf("duplicate name %q", name)
+		}
+		ret = append(ret, name)
+	}
+	return ret, nil
+}
+
+// CreateBatch ライセンスを並行して作成する
+//
+// 作成を始める前にすべての名前を作り、テンプレートの誤りや名前の重複があればエラーを返す。
+// BulkRollbackによる削除は削除保護を無視する。
+func (op *LicenseOp) CreateBatch(ctx context.Context, p LicenseBatchParams, opts BulkOptions) (*BulkResult[License, LicenseID], error) {
+	if p.NameTemplate == "" {
+		return nil, NewError("License.CreateBatch", errors.New("NameTemplate is required"))
+	} else if len(p.Items) == 0 && p.Count <= 0 {
+		return nil, NewError("License.CreateBatch", errors.New("Count or Items is required"))
+	}
+	names, err := p.names()
+	if err != nil {
+		return nil, NewError("License.CreateBatch", err)
+	}
+
+	res := runBulk(ctx, len(names), opts, func(ctx context.Context, item *BulkItemResult[License, LicenseID]) {
+		item.Value, item.Err = op.Create(ctx, CloudHSMSoftwareLicenseCreateParams{
+			Name:        names[item.Index],
+			Description: p.Description,
+			Tags:        slices.Clone(p.Tags),
+		})
+		if item.Value != nil {
+			item.ID = item.Value.ID
+		}
+	})
+
+	if opts.OnFailure == BulkRollback {
+		rollback(ctx, res, opts, func(ctx context.Context, id LicenseID) error {
+			return op.Delete(ctx, id, WithForce())
+		})
+	}
+	return res, res.Err()
+}
+
+// DeleteByTag tagの付いたライセンスをすべて並行して削除する
+//
+// 結果のValueは削除したライセンスで、失敗したものや実行しなかったものはnil。削除保護はライセンスごとに判定する。
+func (op *LicenseOp) DeleteByTag(ctx context.Context, tag string, opts BulkOptions, dopts ...DeleteOption) (*BulkResult[License, LicenseID], error) {
+	licenses, err := op.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	licenses = slices.DeleteFunc(licenses, func(l License) bool {
+		return !slices.Contains(l.Tags, tag)
+	})
+
+	res := runBulk(ctx, len(licenses), opts, func(ctx context.Context, item *BulkItemResult[License, LicenseID]) {
+		item.Err = op.Delete(ctx, licenses[item.Index].ID, dopts...)
+	})
+	for i := range res.Items {
+		item := &res.Items[i]
+		item.ID = licenses[i].ID
+		if item.Err == nil && !item.Skipped {
+			item.Value = &licenses[i]
+		}
+	}
+	return res, res.Err()
+}
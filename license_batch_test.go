@@ -0,0 +1,227 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// licenseStore is an in-memory license API. Creating a license named "bad"
+// fails with 422.
+type licenseStore struct {
+	mu       sync.Mutex
+	seq      int
+	licenses []v1.CloudHSMSoftwareLicense
+}
+
+func (s *licenseStore) add(name string, tags ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	lic := TemplateLicense
+	lic.SetID(fmt.Sprintf("lic-%d", s.seq))
+	lic.SetName(name)
+	lic.SetTags(append([]string{}, tags...))
+	lic.SetCreatedAt(v1.DateTime(time.Now().UTC().Format(time.RFC3339Nano)))
+	s.licenses = append(s.licenses, lic)
+	return lic.ID
+}
+
+func (s *licenseStore) names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ret []string
+	for _, l := range s.licenses {
+		ret = append(ret, l.Name)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+func (s *licenseStore) find(id string) int {
+	return slices.IndexFunc(s.licenses, func(l v1.CloudHSMSoftwareLicense) bool { return l.ID == id })
+}
+
+func (s *licenseStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	id := path.Base(p)
+	if strings.HasSuffix(p, "/licenses") {
+		id = ""
+	}
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		s.mu.Lock()
+		list := append([]v1.CloudHSMSoftwareLicense{}, s.licenses...)
+		s.mu.Unlock()
+		respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMSoftwareLicenseList{
+			Count: len(list), From: v1.NewOptInt(0), Total: v1.NewOptInt(len(list)), Licenses: list,
+		})
+	case r.Method == http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		var req v1.WrappedCreateCloudHSMSoftwareLicense
+		if err := json.Unmarshal(body, &req); err != nil || req.License.Value.Name == "bad" {
+			respondJSON(w, http.StatusUnprocessableEntity, newErrorResponse("invalid"))
+			return
+		}
+		lic := req.License.Value
+		lic.ID = s.add(lic.Name, lic.Tags...)
+		respondJSON(w, http.StatusCreated, &v1.WrappedCreateCloudHSMSoftwareLicense{License: v1.NewOptCreateCloudHSMSoftwareLicense(lic)})
+	default:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i := s.find(id)
+		if i < 0 {
+			respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(s.licenses[i])})
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			var req v1.WrappedCloudHSMSoftwareLicense
+			if err := json.Unmarshal(body, &req); err != nil {
+				respondJSON(w, http.StatusBadRequest, newErrorResponse(err.Error()))
+				return
+			}
+			lic := &s.licenses[i]
+			lic.SetName(req.License.Value.Name)
+			lic.SetDescription(req.License.Value.Description)
+			lic.SetTags(req.License.Value.Tags)
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(*lic)})
+		case http.MethodDelete:
+			s.licenses = slices.Delete(s.licenses, i, i+1)
+			respondJSON(w, http.StatusNoContent, nil)
+		}
+	}
+}
+
+func TestLicenseOp_CreateBatch(t *testing.T) {
+	assert := require.New(t)
+	store := &licenseStore{}
+	api := NewLicenseOp(newTestClientWithHandler(t, store))
+	ctx := context.Background()
+
+	res, err := api.CreateBatch(ctx, LicenseBatchParams{
+		Count:        3,
+		NameTemplate: "app-{{.Index}}",
+		Description:  ref("for app"),
+		Tags:         []string{"cluster=a"},
+	}, BulkOptions{})
+	assert.NoError(err)
+	assert.Len(res.Items, 3)
+	for i, item := range res.Items {
+		assert.Equal(fmt.Sprintf("app-%d", i), item.Value.Name)
+		assert.Equal(ref("for app"), item.Value.Description)
+		assert.Equal([]string{"cluster=a"}, item.Value.Tags)
+	}
+
+	res, err = api.CreateBatch(ctx, LicenseBatchParams{
+		Items:        []string{"x", "y"},
+		NameTemplate: "{{.Item}}-{{.Index}}",
+		Tags:         []string{"cluster=b"},
+	}, BulkOptions{Concurrency: 1})
+	assert.NoError(err)
+	assert.Equal("y-1", res.Items[1].Value.Name)
+	assert.Equal([]string{"app-0", "app-1", "app-2", "x-0", "y-1"}, store.names())
+
+	del, err := api.DeleteByTag(ctx, "cluster=a", BulkOptions{})
+	assert.NoError(err)
+	var deleted []string
+	for _, item := range del.Items {
+		deleted = append(deleted, item.Value.Name)
+	}
+	assert.ElementsMatch([]string{"app-0", "app-1", "app-2"}, deleted)
+	assert.Equal([]string{"x-0", "y-1"}, store.names())
+}
+
+func TestLicenseOp_CreateBatch_Errors(t *testing.T) {
+	assert := require.New(t)
+	store := &licenseStore{}
+	api := NewLicenseOp(newTestClientWithHandler(t, store, WithDeletionProtection(DefaultDeletionProtection)))
+	ctx := context.Background()
+
+	_, err := api.CreateBatch(ctx, LicenseBatchParams{Count: 2, NameTemplate: "same"}, BulkOptions{})
+	assert.ErrorContains(err, `duplicate name "same"`)
+	_, err = api.CreateBatch(ctx, LicenseBatchParams{Count: 2, NameTemplate: "{{.Missing}}"}, BulkOptions{})
+	assert.Error(err)
+	_, err = api.CreateBatch(ctx, LicenseBatchParams{Count: 2}, BulkOptions{})
+	assert.Error(err)
+	_, err = api.CreateBatch(ctx, LicenseBatchParams{NameTemplate: "app-{{.Index}}"}, BulkOptions{})
+	assert.ErrorContains(err, "Count or Items is required")
+	_, err = api.CreateBatch(ctx, LicenseBatchParams{Count: -1, Items: []string{}, NameTemplate: "app-{{.Index}}"}, BulkOptions{})
+	assert.ErrorContains(err, "Count or Items is required")
+
+	// An empty Items is treated like nil, so Count decides how many are created.
+	res, err := api.CreateBatch(ctx, LicenseBatchParams{Count: 2, Items: []string{}, NameTemplate: "empty-{{.Index}}"}, BulkOptions{})
+	assert.NoError(err)
+	assert.Len(res.Items, 2)
+	assert.ElementsMatch([]string{"empty-0", "empty-1"}, store.names())
+	for _, item := range res.Items {
+		assert.NoError(api.Delete(ctx, item.ID, WithForce()))
+	}
+
+	// Licenses created moments ago are protected, but rollback removes them anyway.
+	res, err = api.CreateBatch(ctx, LicenseBatchParams{
+		Items:        []string{"ok", "bad", "never"},
+		NameTemplate: "{{.Item}}",
+	}, BulkOptions{Concurrency: 1, OnFailure: BulkRollback})
+	var berr *BulkError
+	assert.True(errors.As(err, &berr))
+	assert.True(res.Items[0].RolledBack)
+	assert.Error(res.Items[1].Err)
+	assert.True(res.Items[2].Skipped)
+	assert.Empty(store.names())
+}
+
+func TestLicenseOp_DeleteByTag_Failure(t *testing.T) {
+	assert := require.New(t)
+	store := &licenseStore{}
+	store.add("a", "cluster=a")
+	store.add("b", "cluster=a", "protected")
+	store.add("c", "cluster=a")
+	api := NewLicenseOp(newTestClientWithHandler(t, store, WithDeletionProtection(DeletionProtection{Tag: "protected"})))
+
+	res, err := api.DeleteByTag(context.Background(), "cluster=a", BulkOptions{Concurrency: 1, OnFailure: BulkStop})
+	assert.ErrorIs(err, ErrDeletionProtected)
+	assert.Len(res.Items, 3)
+
+	// only the license that was actually deleted carries a Value
+	assert.NoError(res.Items[0].Err)
+	assert.Equal("a", res.Items[0].Value.Name)
+	assert.ErrorIs(res.Items[1].Err, ErrDeletionProtected)
+	assert.Nil(res.Items[1].Value)
+	assert.True(res.Items[2].Skipped)
+	assert.Nil(res.Items[2].Value)
+	assert.Equal([]string{"b", "c"}, store.names())
+}
@@ -0,0 +1,300 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// ReportDimension LicenseReportの集計の軸
+type ReportDimension string
+
+const (
+	DimensionTotal        ReportDimension = "total"
+	DimensionServiceClass ReportDimension = "service_class"
+	DimensionTag          ReportDimension = "tag"
+	DimensionAge          ReportDimension = "age"
+	DimensionAssignment   ReportDimension = "assignment"
+)
+
+// 割り当て状態の集計のキー
+const (
+	AssignmentAssigned   = "assigned"
+	AssignmentUnassigned = "unassigned"
+
+	// AssignmentStale 割り当て先のパーティションまたはクライアントが存在しない
+	AssignmentStale = "stale"
+)
+
+// ReportNoTag タグのないライセンスを数えるキー
+const ReportNoTag = "(none)"
+
+// ReportUnknownAge 作成日時を解釈できないライセンスを数えるキー
+const ReportUnknownAge = "unknown"
+
+// DefaultAgeBuckets LicenseReportOptions.AgeBucketsの既定値
+var DefaultAgeBuckets = []time.Duration{
+	30 * 24 * time.Hour,
+	90 * 24 * time.Hour,
+	180 * 24 * time.Hour,
+	365 * 24 * time.Hour,
+}
+
+// LicenseReportOptions ReportLicensesの動作を指定する
+type LicenseReportOptions struct {
+	// AgeBuckets 経過期間の区切り。昇順に並べる。nilならDefaultAgeBuckets
+	AgeBuckets []time.Duration
+
+	// Now 経過期間の基準時刻。ゼロ値なら現在時刻
+	Now time.Time
+}
+
+// ReportRow 集計の1行
+type ReportRow struct {
+	Dimension ReportDimension `json:"dimension"`
+	Key       string          `json:"key"`
+	Count     int             `json:"count"`
+}
+
+// LicenseReport ライセンスの数と利用状況の集計
+type LicenseReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Licenses ライセンスの数
+	Licenses int `json:"licenses"`
+
+	// Clients 利用可能なパーティションのクライアントの数
+	Clients int `json:"clients"`
+
+	// UnlicensedClients Clientsのうちライセンスが割り当てられていないものの数
+	UnlicensedClients int `json:"unlicensed_clients"`
+
+	// Rows 軸ごとの集計。ServiceClass、タグ、経過期間、割り当て状態の順に並ぶ
+	Rows []ReportRow `json:"rows"`
+}
+
+// ReportLicenses すべてのライセンスとクライアントを取得し、ライセンスの数を集計する
+//
+// タグの集計には割り当てを表すタグを含めない。割り当て状態は割り当て先のクライアントが存在するかどうかも確かめる。
+// 利用可能でないパーティションへの割り当ては確かめられないためassignedとして数える。
+func ReportLicenses(ctx context.Context, client *v1.Client, opts LicenseReportOptions) (*LicenseReport, error) {
+	ctx = BypassCache(ctx)
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	buckets := opts.AgeBuckets
+	if buckets == nil {
+		buckets = DefaultAgeBuckets
+	}
+
+	licenses, err := NewLicenseOp(client).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hsms, err := NewCloudHSMOp(client).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// partitions パーティションのIDから、そのクライアントのIDの集合。利用可能でなければnil
+	partitions := map[PartitionID]map[ClientID]bool{}
+	var clients []Assignment
+	for i := range hsms {
+		hsm := &hsms[i]
+		if !hsm.Available() {
+			partitions[hsm.ID] = nil
+			continue
+		}
+		clientOp, err := NewClientOp(client, hsm)
+		if err != nil {
+			return nil, NewError("ReportLicenses", err)
+		}
+		list, err := clientOp.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ids := map[ClientID]bool{}
+		for _, c := range list {
+			ids[c.ID] = true
+			clients = append(clients, Assignment{PartitionID: hsm.ID, ClientID: c.ID})
+		}
+		partitions[hsm.ID] = ids
+	}
+
+	classes := map[string]int{}
+	tags := map[string]int{}
+	ages := make([]int, len(buckets)+2)
+	assignment := map[string]int{}
+	licensed := map[Assignment]bool{}
+	for _, l := range licenses {
+		classes[string(l.ServiceClass)]++
+
+		tagged := false
+		for _, t := range l.Tags {
+			if !strings.HasPrefix(t, AssignmentTagPrefix) {
+				tags[t]++
+				tagged = true
+			}
+		}
+		if !tagged {
+			tags[ReportNoTag]++
+		}
+
+		if !l.CreatedAt.IsZero() {
+			i := 0
+			for i < len(buckets) && now.Sub(l.CreatedAt) >= buckets[i] {
+				i++
+			}
+			ages[i]++
+		} else {
+			ages[len(ages)-1]++
+		}
+
+		p, c, ok := ParseAssignment(l.Tags)
+		switch ids, found := partitions[p]; {
+		case !ok:
+			assignment[AssignmentUnassigned]++
+		case !found || (ids != nil && !ids[c]):
+			assignment[AssignmentStale]++
+		default:
+			assignment[AssignmentAssigned]++
+			licensed[Assignment{PartitionID: p, ClientID: c}] = true
+		}
+	}
+
+	r := &LicenseReport{
+		GeneratedAt: now.UTC(),
+		Licenses:    len(licenses),
+		Clients:     len(clients),
+	}
+	for _, c := range clients {
+		if !licensed[c] {
+			r.UnlicensedClients++
+		}
+	}
+
+	r.Rows = append(r.Rows, countRows(DimensionServiceClass, classes, func(a, b ReportRow) int {
+		return strings.Compare(a.Key, b.Key)
+	})...)
+	r.Rows = append(r.Rows, countRows(DimensionTag, tags, func(a, b ReportRow) int {
+		return cmp.Or(b.Count-a.Count, strings.Compare(a.Key, b.Key))
+	})...)
+	for i, label := range ageLabels(buckets) {
+		r.Rows = append(r.Rows, ReportRow{Dimension: DimensionAge, Key: label, Count: ages[i]})
+	}
+	for _, key := range []string{AssignmentAssigned, AssignmentUnassigned, AssignmentStale} {
+		r.Rows = append(r.Rows, ReportRow{Dimension: DimensionAssignment, Key: key, Count: assignment[key]})
+	}
+	return r, nil
+}
+
+func countRows(dim ReportDimension, counts map[string]int, order func(a, b ReportRow) int) []ReportRow {
+	ret := make([]ReportRow, 0, len(counts))
+	for k, n := range counts {
+		ret = append(ret, ReportRow{Dimension: dim, Key: k, Count: n})
+	}
+	slices.SortFunc(ret, order)
+	return ret
+}
+
+// ageLabels 経過期間の区切りごとの名前。最後の2つは区切りを超えたものと作成日時が不明なもの
+func ageLabels(buckets []time.Duration) []string {
+	ret := make([]string, 0, len(buckets)+2)
+	prev := "0"
+	for _, b := range buckets {
+		cur := formatAge(b)
+		ret = append(ret, prev+"-"+cur)
+		prev = cur
+	}
+	return append(ret, ">="+prev, ReportUnknownAge)
+}
+
+func formatAge(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+	return d.String()
+}
+
+// totals 総数を表す行
+func (r *LicenseReport) totals() []ReportRow {
+	return []ReportRow{
+		{Dimension: DimensionTotal, Key: "licenses", Count: r.Licenses},
+		{Dimension: DimensionTotal, Key: "clients", Count: r.Clients},
+		{Dimension: DimensionTotal, Key: "unlicensed_clients", Count: r.UnlicensedClients},
+	}
+}
+
+// Count 軸とキーに対応する数。該当する行がなければ0
+func (r *LicenseReport) Count(dim ReportDimension, key string) int {
+	for _, row := range append(r.totals(), r.Rows...) {
+		if row.Dimension == dim && row.Key == key {
+			return row.Count
+		}
+	}
+	return 0
+}
+
+// WriteJSON インデント付きのJSONとして書き出す
+func (r *LicenseReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return NewError("LicenseReport.WriteJSON", err)
+	}
+	return nil
+}
+
+// WriteCSV dimension,key,countの3列のCSVとして書き出す。総数はdimensionがtotalの行になる
+func (r *LicenseReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"dimension", "key", "count"})
+	for _, row := range append(r.totals(), r.Rows...) {
+		_ = cw.Write([]string{string(row.Dimension), row.Key, strconv.Itoa(row.Count)})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return NewError("LicenseReport.WriteCSV", err)
+	}
+	return nil
+}
+
+// WriteTable 列を揃えた表として書き出す
+func (r *LicenseReport) WriteTable(w io.Writer) error {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DIMENSION\tKEY\tCOUNT")
+	for _, row := range append(r.totals(), r.Rows...) {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", row.Dimension, row.Key, row.Count)
+	}
+	_ = tw.Flush()
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return NewError("LicenseReport.WriteTable", err)
+	}
+	return nil
+}
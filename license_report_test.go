@@ -0,0 +1,122 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportLicenses(t *testing.T) {
+	assert := require.New(t)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	store := &licenseStore{}
+	store.add("a", "team=x", AssignmentTag("hsm-1", "client-1"))
+	store.add("b", "team=x", "env=prod")
+	store.add("c", AssignmentTag("hsm-1", "client-9"))
+	store.add("d", AssignmentTag("hsm-gone", "client-1"))
+	for i, age := range []time.Duration{0, 45 * 24 * time.Hour, 400 * 24 * time.Hour} {
+		store.licenses[i].SetCreatedAt(v1.DateTime(now.Add(-age).Format(time.RFC3339)))
+	}
+	store.licenses[3].SetCreatedAt("not a date")
+
+	client := newTestClientWithHandler(t, assignmentHandler(store))
+	r, err := ReportLicenses(context.Background(), client, LicenseReportOptions{Now: now})
+	assert.NoError(err)
+
+	assert.Equal(now, r.GeneratedAt)
+	assert.Equal(4, r.Count(DimensionTotal, "licenses"))
+	assert.Equal(2, r.Clients)
+	assert.Equal(1, r.UnlicensedClients)
+	assert.Equal(4, r.Count(DimensionServiceClass, string(TemplateLicense.ServiceClass)))
+
+	assert.Equal([]ReportRow{
+		{Dimension: DimensionTag, Key: ReportNoTag, Count: 2},
+		{Dimension: DimensionTag, Key: "team=x", Count: 2},
+		{Dimension: DimensionTag, Key: "env=prod", Count: 1},
+	}, rowsOf(r, DimensionTag))
+	assert.Equal([]ReportRow{
+		{Dimension: DimensionAge, Key: "0-30d", Count: 1},
+		{Dimension: DimensionAge, Key: "30d-90d", Count: 1},
+		{Dimension: DimensionAge, Key: "90d-180d", Count: 0},
+		{Dimension: DimensionAge, Key: "180d-365d", Count: 0},
+		{Dimension: DimensionAge, Key: ">=365d", Count: 1},
+		{Dimension: DimensionAge, Key: ReportUnknownAge, Count: 1},
+	}, rowsOf(r, DimensionAge))
+	assert.Equal(1, r.Count(DimensionAssignment, AssignmentAssigned))
+	assert.Equal(1, r.Count(DimensionAssignment, AssignmentUnassigned))
+	assert.Equal(2, r.Count(DimensionAssignment, AssignmentStale))
+}
+
+func rowsOf(r *LicenseReport, dim ReportDimension) []ReportRow {
+	var ret []ReportRow
+	for _, row := range r.Rows {
+		if row.Dimension == dim {
+			ret = append(ret, row)
+		}
+	}
+	return ret
+}
+
+func TestLicenseReport_Write(t *testing.T) {
+	assert := require.New(t)
+	r := &LicenseReport{
+		GeneratedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Licenses:    3,
+		Clients:     2,
+		Rows: []ReportRow{
+			{Dimension: DimensionTag, Key: "team=a,b", Count: 3},
+			{Dimension: DimensionAssignment, Key: AssignmentAssigned, Count: 2},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(r.WriteCSV(&buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(err)
+	assert.Equal([][]string{
+		{"dimension", "key", "count"},
+		{"total", "licenses", "3"},
+		{"total", "clients", "2"},
+		{"total", "unlicensed_clients", "0"},
+		{"tag", "team=a,b", "3"},
+		{"assignment", "assigned", "2"},
+	}, records)
+
+	buf.Reset()
+	assert.NoError(r.WriteTable(&buf))
+	assert.Equal(`DIMENSION   KEY                 COUNT
+total       licenses            3
+total       clients             2
+total       unlicensed_clients  0
+tag         team=a,b            3
+assignment  assigned            2
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(r.WriteJSON(&buf))
+	var decoded LicenseReport
+	assert.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(*r, decoded)
+}
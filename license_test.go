@@ -56,7 +56,7 @@ func TestLicenseOp_Read(t *testing.T) {
 	res, err := api.Read(ctx, "12345")
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateWrappedLicense.GetLicense().Value, *res)
+	assert.Equal(LicenseFromCloudHSMSoftwareLicense(&TemplateLicense), *res)
 }
 
 func TestLicenseOp_Read_404(t *testing.T) {
@@ -88,7 +88,7 @@ func TestLicenseOp_Create(t *testing.T) {
 	})
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateWrappedCreateLicense.GetLicense().Value, *res)
+	assert.Equal(LicenseFromCreateCloudHSMSoftwareLicense(&TemplateCreateLicense), *res)
 }
 
 func TestLicenseOp_Create_422(t *testing.T) {
@@ -111,7 +111,7 @@ func TestLicenseOp_Update(t *testing.T) {
 	ctx := context.Background()
 
 	res, err := api.Update(ctx, "12345", CloudHSMSoftwareLicenseUpdateParams{
-		Description: "Updated Description",
+		Description: ref("Updated Description"),
 		Name:        "Updated Name",
 		Tags: []string{
 			"tag1",
@@ -120,7 +120,7 @@ func TestLicenseOp_Update(t *testing.T) {
 	})
 	assert.NoError(err)
 	assert.NotNil(res)
-	assert.Equal(TemplateWrappedLicense.GetLicense().Value, *res)
+	assert.Equal(LicenseFromCloudHSMSoftwareLicense(&TemplateLicense), *res)
 }
 
 func TestLicenseOp_Update_400(t *testing.T) {
@@ -136,6 +136,70 @@ func TestLicenseOp_Update_400(t *testing.T) {
 	assert.ErrorContains(err, "invalid")
 }
 
+// emptyBody a successful response without the license object
+var emptyBody = map[string]any{}
+
+func TestLicenseOp_Read_Empty(t *testing.T) {
+	assert := require.New(t)
+	client := newTestLicenseClient(emptyBody)
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	license, err := api.Read(ctx, "12345")
+	assert.Nil(license)
+	assert.ErrorIs(err, ErrEmptyResponse)
+}
+
+func TestLicenseOp_Create_Empty(t *testing.T) {
+	assert := require.New(t)
+	client := newTestLicenseClient(emptyBody, http.StatusCreated)
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	license, err := api.Create(ctx, CloudHSMSoftwareLicenseCreateParams{Name: "Test License", Tags: []string{"t"}})
+	assert.Nil(license)
+	assert.ErrorIs(err, ErrEmptyResponse)
+
+	// the license may exist, so the caller is told what to look for
+	var uerr *UnconfirmedCreateError
+	assert.ErrorAs(err, &uerr)
+	assert.Equal(&UnconfirmedCreateError{Resource: "License", Name: "Test License", Tags: []string{"t"}}, uerr)
+}
+
+func TestLicenseOp_Update_Empty(t *testing.T) {
+	assert := require.New(t)
+	var methods []string
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodGet {
+			respondJSON(w, http.StatusOK, TemplateWrappedLicense)
+		} else {
+			respondJSON(w, http.StatusOK, emptyBody)
+		}
+	}))
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	// the update succeeded, so the license is fetched again
+	res, err := api.Update(ctx, "12345", CloudHSMSoftwareLicenseUpdateParams{Name: "Updated Name"})
+	assert.NoError(err)
+	assert.NotNil(res)
+	assert.Equal(LicenseFromCloudHSMSoftwareLicense(&TemplateLicense), *res)
+	assert.Equal([]string{http.MethodPut, http.MethodGet}, methods)
+}
+
+func TestLicenseOp_Update_EmptyRead(t *testing.T) {
+	assert := require.New(t)
+	client := newTestLicenseClient(emptyBody)
+	api := NewLicenseOp(client)
+	ctx := context.Background()
+
+	license, err := api.Update(ctx, "12345", CloudHSMSoftwareLicenseUpdateParams{Name: "Updated Name"})
+	assert.Nil(license)
+	assert.ErrorIs(err, ErrEmptyResponse)
+	assert.Equal("cloudhsm: License.Update: API Error: empty response", err.Error())
+}
+
 func TestLicenseOp_Delete(t *testing.T) {
 	assert := require.New(t)
 	client := newTestLicenseClient(nil, http.StatusNoContent)
@@ -174,16 +238,16 @@ func TestLicenseIntegrated(t *testing.T) {
 
 	// Delete
 	t.Cleanup(func() {
-		err := api.Delete(ctx, created.GetID())
+		err := api.Delete(ctx, created.ID)
 		assert.NoError(err)
 	})
 
 	// Read
-	read, err := api.Read(ctx, created.GetID())
+	read, err := api.Read(ctx, created.ID)
 	assert.NoError(err)
 	assert.NotNil(read)
-	assert.Equal(created.GetID(), read.GetID())
-	assert.Equal(created.GetName(), read.GetName())
+	assert.Equal(created.ID, read.ID)
+	assert.Equal(created.Name, read.Name)
 
 	// List
 	licenses, err := api.List(ctx)
@@ -194,11 +258,11 @@ func TestLicenseIntegrated(t *testing.T) {
 	// Update
 	newDesc := "updated integration test License"
 	updateReq := CloudHSMSoftwareLicenseUpdateParams{
-		Description: newDesc,
-		Name:        read.GetName(),
+		Description: &newDesc,
+		Name:        read.Name,
 	}
-	updated, err := api.Update(ctx, created.GetID(), updateReq)
+	updated, err := api.Update(ctx, created.ID, updateReq)
 	assert.NoError(err)
 	assert.NotNil(updated)
-	assert.Equal(newDesc, updated.GetDescription())
+	assert.Equal(&newDesc, updated.Description)
 }
@@ -0,0 +1,97 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// WithLogger 各Opのメソッド呼び出しをslogで記録する
+//
+// 開始をDebug、成功をInfo、再試行をWarn、失敗をErrorで記録する。
+// 証明書やピアのSecretKeyは記録しない。
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.logger = l
+	}
+}
+
+// LogValue slogで記録する際の表現。空のフィールドは省く
+func (c *Call) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("method", c.Method),
+		slog.String("operation", string(c.Operation)),
+	}
+	for _, a := range []struct{ key, value string }{
+		{"zone", c.Zone},
+		{"cloudhsm_id", c.CloudHSMID},
+		{"client_id", c.ClientID},
+		{"peer_id", c.PeerID},
+		{"license_id", c.LicenseID},
+	} {
+		if a.value != "" {
+			attrs = append(attrs, slog.String(a.key, a.value))
+		}
+	}
+	if c.Attempts > 0 {
+		attrs = append(attrs, slog.Int("attempts", c.Attempts))
+	}
+	if c.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", c.StatusCode))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logging 呼び出しの開始と終了を記録する
+func logging(l *slog.Logger, c *Call, next func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		l.DebugContext(ctx, "cloudhsm: call started", slog.Any("call", c))
+
+		start := time.Now()
+		err := next(ctx)
+		elapsed := slog.Duration("duration", time.Since(start))
+
+		if err != nil {
+			l.ErrorContext(ctx, "cloudhsm: call failed", slog.Any("call", c), elapsed, slog.String("error", Redact(err.Error())))
+		} else {
+			l.InfoContext(ctx, "cloudhsm: call finished", slog.Any("call", c), elapsed)
+		}
+		return err
+	}
+}
+
+// logRetry 再試行に入る旨を記録する
+func logRetry(ctx context.Context, l *slog.Logger, c *Call, attempt int, delay time.Duration, err error) {
+	l.WarnContext(ctx, "cloudhsm: retrying call",
+		slog.Any("call", c),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.String("error", Redact(err.Error())),
+	)
+}
+
+var (
+	pemBlock   = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]+-----[^-]*-----END [A-Z0-9 ]+-----`)
+	secretJSON = regexp.MustCompile(`("(?:SecretKey|Certificate)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+// redact 文字列中の証明書とピアのSecretKeyを伏せる
+func Redact(s string) string {
+	s = pemBlock.ReplaceAllString(s, "[REDACTED]")
+	return secretJSON.ReplaceAllString(s, `$1"[REDACTED]"`)
+}
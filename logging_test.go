@@ -0,0 +1,101 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	ht "github.com/ogen-go/ogen/http"
+	. "github.com/sacloud/cloudhsm-api-go"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var ret []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var rec map[string]any
+		require.NoError(t, dec.Decode(&rec))
+		ret = append(ret, rec)
+	}
+	return ret
+}
+
+func TestLogger_Retry(t *testing.T) {
+	assert := require.New(t)
+	l, buf := newTestLogger()
+	var hits int32
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			respondJSON(w, http.StatusServiceUnavailable, newErrorResponse("later"))
+			return
+		}
+		respondJSON(w, http.StatusOK, TemplateWrappedCloudHSM)
+	}), WithLogger(l), WithRetryPolicy(testRetryPolicy))
+
+	_, err := NewCloudHSMOp(client).Read(context.Background(), "hsm-1")
+	assert.NoError(err)
+
+	recs := logRecords(t, buf)
+	assert.Len(recs, 3)
+	assert.Equal("DEBUG", recs[0]["level"])
+	assert.Equal("WARN", recs[1]["level"])
+	assert.EqualValues(1, recs[1]["attempt"])
+	assert.Equal("INFO", recs[2]["level"])
+	assert.Contains(recs[2], "duration")
+
+	call := recs[2]["call"].(map[string]any)
+	assert.Equal("CloudHSM.Read", call["method"])
+	assert.Equal("hsm-1", call["cloudhsm_id"])
+	assert.EqualValues(2, call["attempts"])
+	assert.EqualValues(http.StatusOK, call["status"])
+	assert.NotContains(call, "client_id")
+}
+
+func TestLogger_RedactsSecrets(t *testing.T) {
+	assert := require.New(t)
+	l, buf := newTestLogger()
+	leak := func(ht.Client) ht.Client {
+		return doerFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New(`rejected {"SecretKey":"pairing-secret","Certificate":"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"}`)
+		})
+	}
+	client := newTestClientWithHandler(t, http.NotFoundHandler(), WithLogger(l), WithHTTPClientMiddleware(leak))
+
+	api, err := NewPeerOp(client, &TemplatePartition)
+	assert.NoError(err)
+	err = api.Create(context.Background(), CloudHSMPeerCreateParams{RouterID: "router-1", SecretKey: "pairing-secret"})
+	assert.Error(err)
+
+	out := buf.String()
+	assert.Contains(out, `"level":"ERROR"`)
+	assert.Contains(out, "router-1")
+	assert.Contains(out, "[REDACTED]")
+	assert.NotContains(out, "pairing-secret")
+	assert.NotContains(out, "MIIB")
+}
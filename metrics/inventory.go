@@ -0,0 +1,160 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cloudhsm "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+var (
+	descUp = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "inventory", "up"),
+		"Whether the last inventory collection succeeded (1) or not (0).",
+		nil, nil,
+	)
+	descPartitions = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "partitions"),
+		"Number of CloudHSM partitions by availability.",
+		[]string{"availability"}, nil,
+	)
+	descLicenses = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "licenses"),
+		"Number of CloudHSM software licenses by service class.",
+		[]string{"service_class"}, nil,
+	)
+	descPeers = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "peers"),
+		"Number of peers per partition by status.",
+		[]string{"partition", "status"}, nil,
+	)
+	descClients = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "clients"),
+		"Number of HSM clients per partition.",
+		[]string{"partition"}, nil,
+	)
+)
+
+// InventoryCollector 収集のたびにCloudHSMOp.List/LicenseOp.List/PeerOp.List/ClientOp.Listを呼び、
+// パーティション・ライセンス・ピア・クライアントの数をゲージとして公開する
+//
+// ピアとクライアントは利用可能(available)なパーティションについてのみ数える。
+type InventoryCollector struct {
+	client  *v1.Client
+	timeout time.Duration
+}
+
+var _ prometheus.Collector = (*InventoryCollector)(nil)
+
+// NewInventoryCollector InventoryCollectorを作成する。timeoutは1回の収集にかける時間の上限
+func NewInventoryCollector(client *v1.Client, timeout time.Duration) *InventoryCollector {
+	return &InventoryCollector{client: client, timeout: timeout}
+}
+
+func (c *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descUp
+	ch <- descPartitions
+	ch <- descLicenses
+	ch <- descPeers
+	ch <- descClients
+}
+
+func (c *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	up := 1.0
+	if err := c.collect(ctx, ch); err != nil {
+		up = 0
+	}
+	ch <- prometheus.MustNewConstMetric(descUp, prometheus.GaugeValue, up)
+}
+
+func (c *InventoryCollector) collect(ctx context.Context, ch chan<- prometheus.Metric) error {
+	hsms, err := cloudhsm.NewCloudHSMOp(c.client).List(ctx)
+	if err != nil {
+		return err
+	}
+
+	partitions := map[v1.AvailabilityEnum]int{}
+	for i := range hsms {
+		partitions[hsms[i].Availability]++
+	}
+	for availability, n := range partitions {
+		ch <- prometheus.MustNewConstMetric(descPartitions, prometheus.GaugeValue, float64(n), string(availability))
+	}
+
+	licenses, err := cloudhsm.NewLicenseOp(c.client).List(ctx)
+	if err != nil {
+		return err
+	}
+
+	classes := map[v1.CloudHSMSoftwareLicenseServiceClassEnum]int{}
+	for i := range licenses {
+		classes[licenses[i].ServiceClass]++
+	}
+	for class, n := range classes {
+		ch <- prometheus.MustNewConstMetric(descLicenses, prometheus.GaugeValue, float64(n), string(class))
+	}
+
+	for i := range hsms {
+		hsm := &hsms[i]
+		if !hsm.Available() {
+			continue
+		}
+		if err := c.collectPartition(ctx, ch, hsm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *InventoryCollector) collectPartition(ctx context.Context, ch chan<- prometheus.Metric, hsm *cloudhsm.Partition) error {
+	peerOp, err := cloudhsm.NewPeerOp(c.client, hsm)
+	if err != nil {
+		return err
+	}
+	peers, err := peerOp.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	statuses := map[v1.CloudHSMPeerStatus]int{}
+	for i := range peers {
+		statuses[peers[i].Status]++
+	}
+	for status, n := range statuses {
+		ch <- prometheus.MustNewConstMetric(descPeers, prometheus.GaugeValue, float64(n), string(hsm.ID), string(status))
+	}
+
+	clientOp, err := cloudhsm.NewClientOp(c.client, hsm)
+	if err != nil {
+		return err
+	}
+	clients, err := clientOp.List(ctx)
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(descClients, prometheus.GaugeValue, float64(len(clients)), string(hsm.ID))
+	return nil
+}
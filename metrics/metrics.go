@@ -0,0 +1,108 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics クラウドHSM APIの利用状況と資産の状態をPrometheusのメトリクスとして公開する
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cloudhsm "github.com/sacloud/cloudhsm-api-go"
+)
+
+// Namespace メトリクス名の接頭辞
+const Namespace = "cloudhsm"
+
+// APIMetrics API呼び出しの回数と所要時間
+//
+// prometheus.Collectorを実装しているので、Registerしたうえで
+// ClientOptionをcloudhsm.NewClientなどに渡して用いる。
+type APIMetrics struct {
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	attempts *prometheus.CounterVec
+}
+
+var _ prometheus.Collector = (*APIMetrics)(nil)
+
+// NewAPIMetrics APIMetricsを作成する
+func NewAPIMetrics() *APIMetrics {
+	return &APIMetrics{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "api",
+			Name:      "calls_total",
+			Help:      "Number of CloudHSM API calls by operation and HTTP status code.",
+		}, []string{"operation", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "api",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of CloudHSM API calls including retries and rate limiting.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "api",
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests sent, including retries.",
+		}, []string{"operation"}),
+	}
+}
+
+func (m *APIMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.calls.Describe(ch)
+	m.duration.Describe(ch)
+	m.attempts.Describe(ch)
+}
+
+func (m *APIMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.calls.Collect(ch)
+	m.duration.Collect(ch)
+	m.attempts.Collect(ch)
+}
+
+// ClientOption 計測を行うためにcloudhsm.NewClientなどに渡すオプション
+func (m *APIMetrics) ClientOption() cloudhsm.ClientOption {
+	return cloudhsm.WithInterceptor(m.Interceptor())
+}
+
+// Interceptor 各Opのメソッド呼び出しを計測する
+func (m *APIMetrics) Interceptor() cloudhsm.Interceptor {
+	return func(ctx context.Context, c *cloudhsm.Call, next func(context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+
+		m.duration.WithLabelValues(c.Operation).Observe(time.Since(start).Seconds())
+		m.calls.WithLabelValues(c.Operation, code(c, err)).Inc()
+		m.attempts.WithLabelValues(c.Operation).Add(float64(c.Attempts))
+		return err
+	}
+}
+
+// code ステータスコードのラベル値。レスポンスを得られずに失敗した場合は"error"
+func code(c *cloudhsm.Call, err error) string {
+	if sc, ok := cloudhsm.StatusCode(err); ok {
+		return strconv.Itoa(sc)
+	} else if err != nil {
+		return "error"
+	} else if c.StatusCode != 0 {
+		return strconv.Itoa(c.StatusCode)
+	} else {
+		return "ok"
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cloudhsm "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/sacloud/cloudhsm-api-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, h http.HandlerFunc, opts ...cloudhsm.ClientOption) *v1.Client {
+	sv := httptest.NewServer(h)
+	t.Cleanup(sv.Close)
+
+	opts = append(opts,
+		cloudhsm.WithHTTPClient(sv.Client()),
+		cloudhsm.WithBasicAuth("token", "secret"),
+	)
+	client, err := cloudhsm.NewClientWithApiUrl(sv.URL+"/cloud/zone/is1a/api/cloud/1.1/", nil, opts...)
+	require.NoError(t, err)
+	return client
+}
+
+func respond(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func hsm(id string, availability v1.AvailabilityEnum) v1.CloudHSM {
+	var ret v1.CloudHSM
+	ret.SetFake()
+	ret.SetID(id)
+	ret.SetAvailability(availability)
+	ret.SetTags([]string{})
+	return ret
+}
+
+func TestAPIMetrics(t *testing.T) {
+	assert := require.New(t)
+	m := metrics.NewAPIMetrics()
+	client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			respond(w, http.StatusNotFound, map[string]any{"is_ok": false})
+			return
+		}
+		respond(w, http.StatusOK, v1.WrappedCloudHSM{CloudHSM: hsm("hsm-1", v1.AvailabilityEnumAvailable)})
+	}, m.ClientOption())
+
+	ctx := context.Background()
+	for range 2 {
+		_, err := cloudhsm.NewCloudHSMOp(client).Read(ctx, "hsm-1")
+		assert.NoError(err)
+	}
+	_, err := cloudhsm.NewCloudHSMOp(client).Read(ctx, "missing")
+	assert.Error(err)
+
+	expected := `
+# HELP cloudhsm_api_calls_total Number of CloudHSM API calls by operation and HTTP status code.
+# TYPE cloudhsm_api_calls_total counter
+cloudhsm_api_calls_total{code="200",operation="CloudhsmCloudhsmsRetrieve"} 2
+cloudhsm_api_calls_total{code="404",operation="CloudhsmCloudhsmsRetrieve"} 1
+`
+	assert.NoError(testutil.CollectAndCompare(m, strings.NewReader(expected), "cloudhsm_api_calls_total"))
+	assert.Equal(1, testutil.CollectAndCount(m, "cloudhsm_api_call_duration_seconds"))
+}
+
+func TestInventoryCollector(t *testing.T) {
+	assert := require.New(t)
+	client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch p := strings.TrimSuffix(r.URL.Path, "/"); {
+		case strings.HasSuffix(p, "/peers"):
+			respond(w, http.StatusOK, v1.CloudHSMPeerList{Peers: []v1.CloudHSMPeer{
+				{ID: "peer-1", Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusUP), Routes: []string{}},
+				{ID: "peer-2", Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusDOWN), Routes: []string{}},
+			}})
+		case strings.HasSuffix(p, "/clients"):
+			var c v1.CloudHSMClient
+			c.SetFake()
+			respond(w, http.StatusOK, v1.PaginatedCloudHSMClientList{
+				Count: 1, From: v1.NewOptInt(0), Total: v1.NewOptInt(1),
+				Clients: []v1.CloudHSMClient{c},
+			})
+		case strings.HasSuffix(p, "/cloudhsm/licenses"):
+			var l v1.CloudHSMSoftwareLicense
+			l.SetFake()
+			l.SetServiceClass(v1.CloudHSMSoftwareLicenseServiceClassEnumCloudCloudhsmLicenseL7)
+			l.SetTags([]string{})
+			respond(w, http.StatusOK, v1.PaginatedCloudHSMSoftwareLicenseList{
+				Count: 2, From: v1.NewOptInt(0), Total: v1.NewOptInt(2),
+				Licenses: []v1.CloudHSMSoftwareLicense{l, l},
+			})
+		case strings.HasSuffix(p, "/cloudhsm/cloudhsms"):
+			respond(w, http.StatusOK, v1.PaginatedCloudHSMList{
+				Count: 2, From: v1.NewOptInt(0), Total: v1.NewOptInt(2),
+				CloudHSMs: []v1.CloudHSM{
+					hsm("hsm-1", v1.AvailabilityEnumAvailable),
+					hsm("hsm-2", v1.AvailabilityEnumPrecreate),
+				},
+			})
+		default:
+			respond(w, http.StatusNotFound, map[string]any{"is_ok": false})
+		}
+	})
+
+	c := metrics.NewInventoryCollector(client, 5*time.Second)
+	expected := `
+# HELP cloudhsm_clients Number of HSM clients per partition.
+# TYPE cloudhsm_clients gauge
+cloudhsm_clients{partition="hsm-1"} 1
+# HELP cloudhsm_inventory_up Whether the last inventory collection succeeded (1) or not (0).
+# TYPE cloudhsm_inventory_up gauge
+cloudhsm_inventory_up 1
+# HELP cloudhsm_licenses Number of CloudHSM software licenses by service class.
+# TYPE cloudhsm_licenses gauge
+cloudhsm_licenses{service_class="cloud/cloudhsm/license/l7"} 2
+# HELP cloudhsm_partitions Number of CloudHSM partitions by availability.
+# TYPE cloudhsm_partitions gauge
+cloudhsm_partitions{availability="available"} 1
+cloudhsm_partitions{availability="precreate"} 1
+# HELP cloudhsm_peers Number of peers per partition by status.
+# TYPE cloudhsm_peers gauge
+cloudhsm_peers{partition="hsm-1",status="DOWN"} 1
+cloudhsm_peers{partition="hsm-1",status="UP"} 1
+`
+	assert.NoError(testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestInventoryCollector_Down(t *testing.T) {
+	assert := require.New(t)
+	client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusForbidden, map[string]any{"is_ok": false})
+	})
+
+	c := metrics.NewInventoryCollector(client, time.Second)
+	expected := `
+# HELP cloudhsm_inventory_up Whether the last inventory collection succeeded (1) or not (0).
+# TYPE cloudhsm_inventory_up gauge
+cloudhsm_inventory_up 0
+`
+	assert.NoError(testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
@@ -0,0 +1,284 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// PartitionID パーティションのID
+type PartitionID string
+
+// ClientID クライアントのID
+type ClientID string
+
+// LicenseID ライセンスのID
+type LicenseID string
+
+// RouterID ローカルルータのID。ピアのIDとしても使う
+type RouterID string
+
+// Partition CloudHSMのパーティション
+//
+// CloudHSMOpのメソッドはすべてこの型を返す。生成された型からはPartitionFromCloudHSMなどで変換する。
+//
+// 日時やアドレスを解釈できない場合はゼロ値になる。
+type Partition struct {
+	ID           PartitionID
+	CreatedAt    time.Time
+	ModifiedAt   time.Time
+	ServiceClass v1.ServiceClassEnum
+	Availability v1.AvailabilityEnum
+	Name         string
+
+	// Description 未設定ならnil
+	Description *string
+
+	Tags []string
+
+	// Ipv4NetworkAddress ネットワークアドレスとプレフィックス長
+	Ipv4NetworkAddress netip.Prefix
+
+	Ipv4Address netip.Addr
+
+	// LocalRouter 接続されたローカルルータ。接続されていなければnil
+	LocalRouter *LocalRouter
+}
+
+// LocalRouter パーティションに接続されたローカルルータ
+//
+// SecretKeyはInventoryやキャッシュ、監査記録などを通じて広まらないよう、
+// フィールドとして公開せず、JSONやYAMLにも書き出さない。
+type LocalRouter struct {
+	// ResourceID 未設定なら空文字列
+	ResourceID RouterID
+
+	secretKey string
+}
+
+// SecretKey ピア接続に用いる秘密鍵。未設定なら空文字列
+func (r *LocalRouter) SecretKey() string {
+	return r.secretKey
+}
+
+// Available 利用可能(available)かどうか。クライアントとピアは利用可能なパーティションでのみ操作できる
+func (p *Partition) Available() bool {
+	return p.Availability == v1.AvailabilityEnumAvailable
+}
+
+// Client CloudHSMのクライアント
+type Client struct {
+	ID           ClientID
+	CreatedAt    time.Time
+	ModifiedAt   time.Time
+	Availability v1.AvailabilityEnum
+	Name         string
+	Certificate  string
+}
+
+// Peer パーティションとローカルルータのピア接続
+type Peer struct {
+	// ID ローカルルータのID
+	ID RouterID
+
+	// Index 未設定ならnil
+	Index *int
+
+	// Status 未設定なら空文字列
+	Status v1.CloudHSMPeerStatus
+
+	Routes []string
+}
+
+// License CloudHSMのソフトウェアライセンス
+type License struct {
+	ID           LicenseID
+	CreatedAt    time.Time
+	ModifiedAt   time.Time
+	ServiceClass v1.CloudHSMSoftwareLicenseServiceClassEnum
+	Name         string
+
+	// Description 未設定ならnil
+	Description *string
+
+	Tags []string
+}
+
+// PartitionFromCloudHSM v1.CloudHSMをPartitionに変換する
+func PartitionFromCloudHSM(v *v1.CloudHSM) Partition {
+	ret := Partition{
+		ID:                 PartitionID(v.GetID()),
+		CreatedAt:          toTime(v.GetCreatedAt()),
+		ModifiedAt:         toTime(v.GetModifiedAt()),
+		ServiceClass:       v.GetServiceClass(),
+		Availability:       v.GetAvailability(),
+		Name:               v.GetName(),
+		Description:        fromOpt(v.GetDescription()),
+		Tags:               slices.Clone(v.GetTags()),
+		Ipv4NetworkAddress: toPrefix(v.GetIpv4NetworkAddress(), v.GetIpv4PrefixLength()),
+		Ipv4Address:        toAddr(v.GetIpv4Address()),
+	}
+	if r, ok := v.GetLocalRouter().Get(); ok {
+		ret.LocalRouter = &LocalRouter{
+			ResourceID: RouterID(r.GetResourceID().Or("")),
+			secretKey:  r.GetSecretKey().Or(""),
+		}
+	}
+	return ret
+}
+
+// PartitionFromCreateCloudHSM v1.CreateCloudHSMをPartitionに変換する
+func PartitionFromCreateCloudHSM(v *v1.CreateCloudHSM) Partition {
+	return Partition{
+		ID:                 PartitionID(v.GetID()),
+		CreatedAt:          toTime(v.GetCreatedAt()),
+		ModifiedAt:         toTime(v.GetModifiedAt()),
+		ServiceClass:       v.GetServiceClass(),
+		Availability:       v.GetAvailability(),
+		Name:               v.GetName(),
+		Description:        fromOpt(v.GetDescription()),
+		Tags:               slices.Clone(v.GetTags()),
+		Ipv4NetworkAddress: toPrefix(v.GetIpv4NetworkAddress(), v.GetIpv4PrefixLength()),
+		Ipv4Address:        toAddr(v.GetIpv4Address()),
+	}
+}
+
+// ClientFromCloudHSMClient v1.CloudHSMClientをClientに変換する
+func ClientFromCloudHSMClient(v *v1.CloudHSMClient) Client {
+	return Client{
+		ID:           ClientID(v.GetID()),
+		CreatedAt:    toTime(v.GetCreatedAt()),
+		ModifiedAt:   toTime(v.GetModifiedAt()),
+		Availability: v.GetAvailability(),
+		Name:         v.GetName(),
+		Certificate:  v.GetCertificate(),
+	}
+}
+
+// ClientFromCreateCloudHSMClient v1.CreateCloudHSMClientをClientに変換する
+func ClientFromCreateCloudHSMClient(v *v1.CreateCloudHSMClient) Client {
+	return Client{
+		ID:           ClientID(v.GetID()),
+		CreatedAt:    toTime(v.GetCreatedAt()),
+		ModifiedAt:   toTime(v.GetModifiedAt()),
+		Availability: v.GetAvailability(),
+		Name:         v.GetName(),
+		Certificate:  v.GetCertificate(),
+	}
+}
+
+// PeerFromCloudHSMPeer v1.CloudHSMPeerをPeerに変換する
+func PeerFromCloudHSMPeer(v *v1.CloudHSMPeer) Peer {
+	ret := Peer{
+		ID:     RouterID(v.GetID()),
+		Status: v.GetStatus().Or(""),
+		Routes: slices.Clone(v.GetRoutes()),
+	}
+	if i, ok := v.GetIndex().Get(); ok {
+		ret.Index = &i
+	}
+	return ret
+}
+
+// PeerFromCreateCloudHSMPeer v1.CreateCloudHSMPeerをPeerに変換する。SecretKeyは含まない
+func PeerFromCreateCloudHSMPeer(v *v1.CreateCloudHSMPeer) Peer {
+	return Peer{ID: RouterID(v.GetID())}
+}
+
+// LicenseFromCloudHSMSoftwareLicense v1.CloudHSMSoftwareLicenseをLicenseに変換する
+func LicenseFromCloudHSMSoftwareLicense(v *v1.CloudHSMSoftwareLicense) License {
+	return License{
+		ID:           LicenseID(v.GetID()),
+		CreatedAt:    toTime(v.GetCreatedAt()),
+		ModifiedAt:   toTime(v.GetModifiedAt()),
+		ServiceClass: v.GetServiceClass(),
+		Name:         v.GetName(),
+		Description:  nonEmpty(v.GetDescription()),
+		Tags:         slices.Clone(v.GetTags()),
+	}
+}
+
+// LicenseFromCreateCloudHSMSoftwareLicense v1.CreateCloudHSMSoftwareLicenseをLicenseに変換する
+func LicenseFromCreateCloudHSMSoftwareLicense(v *v1.CreateCloudHSMSoftwareLicense) License {
+	return License{
+		ID:           LicenseID(v.GetID()),
+		CreatedAt:    toTime(v.GetCreatedAt()),
+		ModifiedAt:   toTime(v.GetModifiedAt()),
+		ServiceClass: v.GetServiceClass(),
+		Name:         v.GetName(),
+		Description:  nonEmpty(v.GetDescription().Or("")),
+		Tags:         slices.Clone(v.GetTags()),
+	}
+}
+
+// convertAll 生成された型のスライスを変換する
+func convertAll[T, U any](list []T, f func(*T) U) []U {
+	ret := make([]U, 0, len(list))
+	for i := range list {
+		ret = append(ret, f(&list[i]))
+	}
+	return ret
+}
+
+// toTime APIの日時をtime.Timeに変換する。解釈できなければゼロ値
+func toTime(v v1.DateTime) time.Time {
+	t, _ := parseDateTime(v)
+	return t
+}
+
+// toAddr 解釈できなければゼロ値
+func toAddr(s string) netip.Addr {
+	a, _ := netip.ParseAddr(s)
+	return a
+}
+
+// toPrefix ネットワークアドレスとプレフィックス長をnetip.Prefixにする。解釈できなければゼロ値
+func toPrefix(addr string, bits int) netip.Prefix {
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(a, bits)
+}
+
+// fromPrefix netip.PrefixをAPIのネットワークアドレスとプレフィックス長に戻す。ゼロ値なら空文字列と0
+func fromPrefix(p netip.Prefix) (string, int) {
+	if !p.IsValid() {
+		return "", 0
+	}
+	return p.Addr().String(), p.Bits()
+}
+
+// nonEmpty 空文字列ならnil
+//
+// ライセンスの説明は取得時には省略されず空文字列になるため、作成時の未設定と揃える。
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// fromOpt 未設定ならnil
+func fromOpt(v v1.OptString) *string {
+	if s, ok := v.Get(); ok {
+		return &s
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionFromCloudHSM(t *testing.T) {
+	assert := require.New(t)
+
+	hsm := TemplateCloudHSM
+	hsm.SetLocalRouter(v1.NewNilCloudHSMLocalRouter(v1.CloudHSMLocalRouter{ResourceID: v1.NewOptString("router-0")}))
+	p := PartitionFromCloudHSM(&hsm)
+	assert.Equal(&LocalRouter{ResourceID: "router-0"}, p.LocalRouter)
+	assert.Equal(&TemplateCloudHSM.Description.Value, p.Description)
+	assert.True(p.Available())
+
+	// The router's secret is reachable only through the accessor, never through JSON.
+	hsm.SetLocalRouter(v1.NewNilCloudHSMLocalRouter(v1.CloudHSMLocalRouter{ResourceID: v1.NewOptString("router-0"), SecretKey: v1.NewOptString("TOPSECRET")}))
+	p = PartitionFromCloudHSM(&hsm)
+	assert.Equal("TOPSECRET", p.LocalRouter.SecretKey())
+	j, err := json.Marshal(p)
+	assert.NoError(err)
+	assert.NotContains(string(j), "TOPSECRET")
+
+	// Tags are copied so callers cannot modify the source.
+	p.Tags[0] = "changed"
+	assert.Equal(TemplateTags, hsm.Tags)
+
+	hsm.SetDescription(v1.OptString{})
+	hsm.SetLocalRouter(v1.NilCloudHSMLocalRouter{Null: true})
+	p = PartitionFromCloudHSM(&hsm)
+	assert.Nil(p.LocalRouter)
+	assert.Nil(p.Description)
+
+	// Fields the API returned in an unexpected format become zero values.
+	assert.True(p.CreatedAt.IsZero())
+	assert.False(p.Ipv4NetworkAddress.IsValid())
+	assert.False(p.Ipv4Address.IsValid())
+
+	hsm.SetID("hsm-1")
+	hsm.SetCreatedAt("2025-06-01T09:00:00+09:00")
+	hsm.SetIpv4NetworkAddress("192.168.0.0")
+	hsm.SetIpv4PrefixLength(28)
+	hsm.SetIpv4Address("192.168.0.5")
+	p = PartitionFromCloudHSM(&hsm)
+	assert.Equal(PartitionID("hsm-1"), p.ID)
+	assert.True(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Equal(p.CreatedAt))
+	assert.Equal(netip.MustParsePrefix("192.168.0.0/28"), p.Ipv4NetworkAddress)
+	assert.Equal(netip.MustParseAddr("192.168.0.5"), p.Ipv4Address)
+}
+
+func TestCreateConverters(t *testing.T) {
+	assert := require.New(t)
+
+	// The create and read shapes of the same resource convert to equal values.
+	var created v1.CreateCloudHSM
+	created.SetFake()
+	created.SetTags(TemplateTags)
+	created.SetAvailability(v1.AvailabilityEnumAvailable)
+	read := TemplateCloudHSM
+	read.SetLocalRouter(v1.NilCloudHSMLocalRouter{Null: true})
+	assert.Equal(PartitionFromCloudHSM(&read), PartitionFromCreateCloudHSM(&created))
+
+	assert.Equal(ClientFromCloudHSMClient(&TemplateCloudHSMClient), ClientFromCreateCloudHSMClient(&TemplateCreateCloudHSMClient))
+
+	lic := LicenseFromCreateCloudHSMSoftwareLicense(&TemplateCreateLicense)
+	assert.Equal(LicenseFromCloudHSMSoftwareLicense(&TemplateLicense), lic)
+	assert.Equal(LicenseID(TemplateLicense.ID), lic.ID)
+
+	// An unset description is nil on both shapes, even though the read shape always has one.
+	var bare v1.CreateCloudHSMSoftwareLicense
+	assert.Nil(LicenseFromCreateCloudHSMSoftwareLicense(&bare).Description)
+	assert.Nil(LicenseFromCloudHSMSoftwareLicense(&v1.CloudHSMSoftwareLicense{}).Description)
+	bare.SetDescription(v1.NewOptString(""))
+	assert.Equal(LicenseFromCloudHSMSoftwareLicense(&v1.CloudHSMSoftwareLicense{}), LicenseFromCreateCloudHSMSoftwareLicense(&bare))
+
+	assert.Equal(Peer{ID: "router-1"}, PeerFromCreateCloudHSMPeer(&v1.CreateCloudHSMPeer{ID: "router-1", SecretKey: "secret"}))
+}
+
+func TestPeerFromCloudHSMPeer(t *testing.T) {
+	assert := require.New(t)
+
+	p := PeerFromCloudHSMPeer(&v1.CloudHSMPeer{
+		ID:     "router-1",
+		Index:  v1.NewOptInt(0),
+		Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusUP),
+		Routes: []string{"10.0.0.0/24"},
+	})
+	assert.Equal(Peer{ID: "router-1", Index: ref(0), Status: v1.CloudHSMPeerStatusUP, Routes: []string{"10.0.0.0/24"}}, p)
+
+	p = PeerFromCloudHSMPeer(&v1.CloudHSMPeer{ID: "router-2"})
+	assert.Nil(p.Index)
+	assert.Empty(p.Status)
+}
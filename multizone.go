@@ -0,0 +1,205 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/sacloud/saclient-go"
+)
+
+// Zones クラウドHSMが提供されているゾーンの一覧
+var Zones = []string{"is1a", "is1b", "tk1a", "tk1b"}
+
+// Zoned ゾーン横断操作の結果。どのゾーンから得られたかを併せて保持する
+type Zoned[T any] struct {
+	Zone  string
+	Value T
+}
+
+// ZoneError 特定のゾーンで発生したエラー
+type ZoneError struct {
+	Zone string
+	Err  error
+}
+
+func (e *ZoneError) Error() string {
+	return e.Zone + ": " + e.Err.Error()
+}
+
+func (e *ZoneError) Unwrap() error {
+	return e.Err
+}
+
+// MultiZoneError ゾーン横断操作で一部またはすべてのゾーンが失敗したことを表す
+type MultiZoneError struct {
+	Errors []*ZoneError
+}
+
+func (e *MultiZoneError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, i := range e.Errors {
+		msgs = append(msgs, i.Error())
+	}
+	return "cloudhsm: failed in some zones: " + strings.Join(msgs, "; ")
+}
+
+func (e *MultiZoneError) Unwrap() []error {
+	ret := make([]error, 0, len(e.Errors))
+	for _, i := range e.Errors {
+		ret = append(ret, i)
+	}
+	return ret
+}
+
+// MultiZoneClient ゾーンごとに作成したv1.Clientの集合
+type MultiZoneClient struct {
+	zones   []string
+	clients map[string]*v1.Client
+}
+
+// NewMultiZoneClient 指定したゾーン(nilならZones)ごとにv1.Clientを作成する
+//
+// optsは各ゾーンのクライアントに渡す。WithZoneとWithRootURLは無視する。
+// WithRateLimiterやWithAuditHookなどで渡したものはすべてのゾーンで共有するが、
+// WithCacheはゾーンごとに同じ設定のCacheを作成する。
+func NewMultiZoneClient(client saclient.ClientAPI, zones []string, opts ...ClientOption) (*MultiZoneClient, error) {
+	if len(zones) == 0 {
+		zones = Zones
+	}
+
+	cfg := newClientConfig("", opts...)
+	endpoint := DefaultEndpoint
+	if client != nil {
+		ep, _, err := resolveEndpoint(client, cfg.serviceKey)
+		if err != nil {
+			return nil, NewError("NewMultiZoneClient", err)
+		}
+		endpoint = ep
+	}
+	if cfg.endpoint != "" {
+		endpoint = cfg.endpoint
+	}
+
+	ret := &MultiZoneClient{
+		zones:   make([]string, 0, len(zones)),
+		clients: make(map[string]*v1.Client, len(zones)),
+	}
+	for _, zone := range zones {
+		if _, ok := ret.clients[zone]; ok {
+			continue
+		}
+		apiURL := zoneAPIURL(endpoint, zone)
+		zoneOpts := append(slices.Clone(opts), WithRootURL(apiURL))
+		if cfg.cache != nil {
+			zoneOpts = append(zoneOpts, WithCache(NewCache(cfg.cache.opts)))
+		}
+		c, err := NewClientWithApiUrl(apiURL, client, zoneOpts...)
+		if err != nil {
+			return nil, NewError("NewMultiZoneClient", err)
+		}
+		ret.zones = append(ret.zones, zone)
+		ret.clients[zone] = c
+	}
+
+	return ret, nil
+}
+
+// Zones 対象となっているゾーンの一覧
+func (m *MultiZoneClient) Zones() []string {
+	return append([]string(nil), m.zones...)
+}
+
+// Client 指定したゾーン向けのv1.Client
+func (m *MultiZoneClient) Client(zone string) (*v1.Client, bool) {
+	c, ok := m.clients[zone]
+	return c, ok
+}
+
+// ListAllCloudHSMs すべてのゾーンのCloudHSMパーティションを列挙する
+//
+// 一部のゾーンが失敗した場合でも成功したゾーンの結果は返し、失敗したゾーンは*MultiZoneErrorで報告する。
+func (m *MultiZoneClient) ListAllCloudHSMs(ctx context.Context) ([]Zoned[Partition], error) {
+	return eachZone(ctx, m, func(ctx context.Context, client *v1.Client) ([]Partition, error) {
+		return NewCloudHSMOp(client).List(ctx)
+	})
+}
+
+// ListAllLicenses すべてのゾーンのライセンスを列挙する
+func (m *MultiZoneClient) ListAllLicenses(ctx context.Context) ([]Zoned[License], error) {
+	return eachZone(ctx, m, func(ctx context.Context, client *v1.Client) ([]License, error) {
+		return NewLicenseOp(client).List(ctx)
+	})
+}
+
+// FindCloudHSMsByName 名前が一致するCloudHSMパーティションをすべてのゾーンから探す
+func (m *MultiZoneClient) FindCloudHSMsByName(ctx context.Context, name string) ([]Zoned[Partition], error) {
+	all, err := m.ListAllCloudHSMs(ctx)
+	return filterZoned(all, func(i *Partition) bool { return i.Name == name }), err
+}
+
+// FindLicensesByName 名前が一致するライセンスをすべてのゾーンから探す
+func (m *MultiZoneClient) FindLicensesByName(ctx context.Context, name string) ([]Zoned[License], error) {
+	all, err := m.ListAllLicenses(ctx)
+	return filterZoned(all, func(i *License) bool { return i.Name == name }), err
+}
+
+func eachZone[T any](
+	ctx context.Context,
+	m *MultiZoneClient,
+	f func(context.Context, *v1.Client) ([]T, error),
+) ([]Zoned[T], error) {
+	results := make([][]T, len(m.zones))
+	errs := make([]error, len(m.zones))
+
+	var wg sync.WaitGroup
+	for i, zone := range m.zones {
+		wg.Go(func() {
+			results[i], errs[i] = f(ctx, m.clients[zone])
+		})
+	}
+	wg.Wait()
+
+	var ret []Zoned[T]
+	var failed []*ZoneError
+	for i, zone := range m.zones {
+		if errs[i] != nil {
+			failed = append(failed, &ZoneError{Zone: zone, Err: errs[i]})
+			continue
+		}
+		for _, v := range results[i] {
+			ret = append(ret, Zoned[T]{Zone: zone, Value: v})
+		}
+	}
+
+	if len(failed) > 0 {
+		return ret, &MultiZoneError{Errors: failed}
+	}
+	return ret, nil
+}
+
+func filterZoned[T any](all []Zoned[T], pred func(*T) bool) []Zoned[T] {
+	var ret []Zoned[T]
+	for i := range all {
+		if pred(&all[i].Value) {
+			ret = append(ret, all[i])
+		}
+	}
+	return ret
+}
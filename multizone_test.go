@@ -0,0 +1,167 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/sacloud/saclient-go"
+	"github.com/stretchr/testify/require"
+)
+
+var testingClientForMultiZone saclient.Client
+
+// newTestMultiZoneClient returns a MultiZoneClient whose every zone points to
+// the same test server.  The handler receives the zone name.
+func newTestMultiZoneClient(t *testing.T, h func(zone string, w http.ResponseWriter, r *http.Request), zones []string, opts ...ClientOption) *MultiZoneClient {
+	sv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zone, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		w.Header().Set("Content-Type", "application/json")
+		h(zone, w, r)
+	}))
+	t.Cleanup(sv.Close)
+
+	api, err := theClient.DupWith(saclient.WithTestServer(sv))
+	require.NoError(t, err)
+	require.NoError(t, api.SetEnviron([]string{"SAKURA_ENDPOINTS_CLOUDHSM=" + sv.URL}))
+
+	ret, err := NewMultiZoneClient(api, zones, opts...)
+	require.NoError(t, err)
+	return ret
+}
+
+func zonedCloudHSM(zone string) v1.CloudHSM {
+	ret := TemplateCloudHSM
+	ret.SetID(zone + "-id")
+	ret.SetName("hsm-" + zone)
+	return ret
+}
+
+func TestNewMultiZoneClient(t *testing.T) {
+	assert := require.New(t)
+	m, err := NewMultiZoneClient(&testingClientForMultiZone, nil)
+	assert.NoError(err)
+	assert.Equal(Zones, m.Zones())
+
+	for _, zone := range Zones {
+		c, ok := m.Client(zone)
+		assert.True(ok)
+		assert.NotNil(c)
+	}
+	_, ok := m.Client("nowhere")
+	assert.False(ok)
+}
+
+func TestMultiZoneClient_ListAllCloudHSMs(t *testing.T) {
+	assert := require.New(t)
+	m := newTestMultiZoneClient(t, func(zone string, w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(v1.PaginatedCloudHSMList{
+			Count:     1,
+			From:      v1.NewOptInt(0),
+			Total:     v1.NewOptInt(1),
+			CloudHSMs: []v1.CloudHSM{zonedCloudHSM(zone)},
+		})
+	}, []string{"is1a", "tk1b"})
+
+	res, err := m.ListAllCloudHSMs(context.Background())
+	assert.NoError(err)
+	assert.Len(res, 2)
+	assert.Equal("is1a", res[0].Zone)
+	assert.Equal(PartitionID("is1a-id"), res[0].Value.ID)
+	assert.Equal("tk1b", res[1].Zone)
+	assert.Equal(PartitionID("tk1b-id"), res[1].Value.ID)
+}
+
+func TestMultiZoneClient_Options(t *testing.T) {
+	assert := require.New(t)
+	var calls atomic.Int32
+	m := newTestMultiZoneClient(t, func(zone string, w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(v1.PaginatedCloudHSMList{
+			Count:     1,
+			From:      v1.NewOptInt(0),
+			Total:     v1.NewOptInt(1),
+			CloudHSMs: []v1.CloudHSM{zonedCloudHSM(zone)},
+		})
+	}, []string{"is1a", "tk1b"},
+		// WithZone must not redirect every zone client to a single zone
+		WithZone("is1b"),
+		WithInterceptor(func(ctx context.Context, c *Call, next func(context.Context) error) error {
+			calls.Add(1)
+			return next(ctx)
+		}),
+	)
+
+	res, err := m.ListAllCloudHSMs(context.Background())
+	assert.NoError(err)
+	assert.Len(res, 2)
+	assert.Equal("is1a", res[0].Zone)
+	assert.Equal("tk1b", res[1].Zone)
+	assert.Equal(int32(2), calls.Load())
+}
+
+func TestMultiZoneClient_ListAllLicenses_PartialFailure(t *testing.T) {
+	assert := require.New(t)
+	m := newTestMultiZoneClient(t, func(zone string, w http.ResponseWriter, r *http.Request) {
+		if zone == "is1b" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(newErrorResponse("forbidden"))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v1.PaginatedCloudHSMSoftwareLicenseList{
+			Count:    1,
+			From:     v1.NewOptInt(0),
+			Total:    v1.NewOptInt(1),
+			Licenses: []v1.CloudHSMSoftwareLicense{TemplateLicense},
+		})
+	}, []string{"is1a", "is1b"})
+
+	res, err := m.ListAllLicenses(context.Background())
+	assert.Error(err)
+	assert.Len(res, 1)
+	assert.Equal("is1a", res[0].Zone)
+
+	var mze *MultiZoneError
+	assert.True(errors.As(err, &mze))
+	assert.Len(mze.Errors, 1)
+	assert.Equal("is1b", mze.Errors[0].Zone)
+	assert.ErrorContains(err, "is1b")
+}
+
+func TestMultiZoneClient_FindCloudHSMsByName(t *testing.T) {
+	assert := require.New(t)
+	m := newTestMultiZoneClient(t, func(zone string, w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(v1.PaginatedCloudHSMList{
+			Count:     1,
+			From:      v1.NewOptInt(0),
+			Total:     v1.NewOptInt(1),
+			CloudHSMs: []v1.CloudHSM{zonedCloudHSM(zone)},
+		})
+	}, nil)
+
+	res, err := m.FindCloudHSMsByName(context.Background(), "hsm-tk1a")
+	assert.NoError(err)
+	assert.Len(res, 1)
+	assert.Equal("tk1a", res[0].Zone)
+	assert.Equal(PartitionID("tk1a-id"), res[0].Value.ID)
+}
@@ -0,0 +1,333 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Notification 通知1件分。WatcherのEventか監査記録から作る
+type Notification struct {
+	// Source "watcher"か"audit"
+	Source string `json:"source"`
+
+	// Type EventのTypeか、監査記録のMethod(License.Deleteなど)
+	Type string `json:"type"`
+
+	// Resource "Partition"、"Client"、"Peer"、"License"のいずれか
+	Resource  string `json:"resource"`
+	ID        string `json:"id,omitempty"`
+	Partition string `json:"partition,omitempty"`
+
+	// Summary 1行の説明
+	Summary string    `json:"summary"`
+	Time    time.Time `json:"time"`
+
+	Event *Event      `json:"event,omitempty"`
+	Audit *AuditEntry `json:"audit,omitempty"`
+}
+
+// NotificationFromEvent WatcherのEventから通知を作る
+func NotificationFromEvent(e *Event) *Notification {
+	summary := fmt.Sprintf("%s %s %s", e.Resource, e.ID, e.Type)
+	if len(e.Changes) > 0 {
+		var parts []string
+		for _, c := range e.Changes {
+			parts = append(parts, fmt.Sprintf("%s: %s -> %s", c.Field, formatValue(c.Old), formatValue(c.New)))
+		}
+		summary += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return &Notification{
+		Source:    "watcher",
+		Type:      string(e.Type),
+		Resource:  e.Resource,
+		ID:        e.ID,
+		Partition: e.Partition,
+		Summary:   summary,
+		Time:      e.Time,
+		Event:     e,
+	}
+}
+
+// NotificationFromAudit 監査記録から通知を作る
+func NotificationFromAudit(e *AuditEntry) *Notification {
+	n := &Notification{Source: "audit", Type: e.Method, Time: e.Time, Audit: e}
+	switch {
+	case e.PeerID != "":
+		n.Resource, n.ID, n.Partition = "Peer", e.PeerID, e.CloudHSMID
+	case e.ClientID != "":
+		n.Resource, n.ID, n.Partition = "Client", e.ClientID, e.CloudHSMID
+	case e.LicenseID != "" || strings.HasPrefix(e.Method, "License."):
+		n.Resource, n.ID = "License", e.LicenseID
+	default:
+		n.Resource, n.ID = "Partition", e.CloudHSMID
+	}
+
+	actor := e.Actor
+	if actor == "" {
+		actor = "unknown"
+	}
+	n.Summary = fmt.Sprintf("%s: %s %s %s", actor, e.Method, n.ID, e.Outcome)
+	if e.Error != "" {
+		n.Summary += ": " + e.Error
+	}
+	return n
+}
+
+// Notifier 通知の送り先
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// NotifyEvents chが閉じるまでWatcherのEventをNotifierに送る。失敗はonErrorに渡す(nilなら無視する)
+func NotifyEvents(ctx context.Context, n Notifier, ch <-chan Event, onError func(error)) {
+	for e := range ch {
+		if err := n.Notify(ctx, NotificationFromEvent(&e)); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// SlackTemplate Slackの受信Webhook向けのテンプレート
+const SlackTemplate = `{"text": {{json .Summary}}}`
+
+// WebhookOptions Webhookの動作を指定する
+type WebhookOptions struct {
+	// URL 送り先
+	URL string
+
+	// Template リクエストボディを作るtext/templateのテンプレート。Notificationが渡され、
+	// 値をJSONとして埋め込む関数jsonが使える。空ならNotificationをそのままJSONにする
+	Template string
+
+	// Secret 空でなければ、リクエストにHMAC-SHA256による署名を付ける。VerifyWebhookSignatureで検証できる
+	Secret []byte
+
+	// Header リクエストに加えるヘッダ
+	Header http.Header
+
+	// Retry 再試行の方針。nilならDefaultRetryPolicy
+	Retry *RetryPolicy
+
+	// AuditRetry AuditHookとして使う場合の再試行の方針。nilなら再試行しない
+	//
+	// 監査記録は変更操作の中で送るため、Retryとは別に指定する。
+	AuditRetry *RetryPolicy
+
+	// AuditTimeout AuditHookとして使う場合に、再試行を含めて送信を待つ時間。0なら5秒
+	AuditTimeout time.Duration
+
+	// HTTPClient nilならhttp.DefaultClient
+	HTTPClient *http.Client
+
+	// IncludeSnapshots 監査記録の通知に操作前後のリソース(Before/After)を含める。
+	// falseなら含めない。含める場合もSecretKeyは含まれない
+	IncludeSnapshots bool
+}
+
+// 署名に用いるヘッダ
+const (
+	WebhookTimestampHeader = "X-CloudHSM-Timestamp"
+	WebhookSignatureHeader = "X-CloudHSM-Signature"
+)
+
+// Webhook 通知をJSONとしてPOSTするNotifier
+//
+// AuditHookとしても使える。その場合は変更操作のたびに送信を終えるまで待つため、
+// 送り先が遅くても操作を長く止めないよう、AuditTimeoutで打ち切り、既定では再試行しない。
+type Webhook struct {
+	opts WebhookOptions
+	tmpl *template.Template
+}
+
+var (
+	_ Notifier  = (*Webhook)(nil)
+	_ AuditHook = (*Webhook)(nil)
+)
+
+// NewWebhook Webhookを作成する。テンプレートを解釈できなければエラーを返す
+func NewWebhook(opts WebhookOptions) (*Webhook, error) {
+	if opts.URL == "" {
+		return nil, NewError("NewWebhook", errors.New("URL is required"))
+	}
+	if opts.Retry == nil {
+		p := DefaultRetryPolicy
+		opts.Retry = &p
+	}
+	if opts.AuditRetry == nil {
+		opts.AuditRetry = &RetryPolicy{MaxAttempts: 1}
+	}
+	if opts.AuditTimeout <= 0 {
+		opts.AuditTimeout = 5 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	w := &Webhook{opts: opts}
+	if opts.Template != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": templateJSON}).Parse(opts.Template)
+		if err != nil {
+			return nil, NewError("NewWebhook", err)
+		}
+		w.tmpl = tmpl
+	}
+	return w, nil
+}
+
+func templateJSON(v any) (string, error) {
+	j, err := json.Marshal(v)
+	return string(j), err
+}
+
+// WebhookError 送り先が2xx以外を返したことを表す
+type WebhookError struct {
+	StatusCode int
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d", e.StatusCode)
+}
+
+// HTTPStatusCode RetryPolicy.StatusCodesによる再試行の判定に用いる
+func (e *WebhookError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// Notify 通知を送る。再試行の対象となる失敗の場合は再試行する
+func (w *Webhook) Notify(ctx context.Context, n *Notification) error {
+	if err := w.send(ctx, n, w.opts.Retry); err != nil {
+		return NewError("Webhook.Notify", err)
+	}
+	return nil
+}
+
+// Audit 監査記録を通知する。IncludeSnapshotsを指定しない限り、操作前後のリソースは送らない
+//
+// AuditTimeoutまでに送れなければ諦め、再試行はAuditRetryに従う。
+func (w *Webhook) Audit(ctx context.Context, e *AuditEntry) error {
+	if !w.opts.IncludeSnapshots {
+		c := *e
+		c.Before, c.After = nil, nil
+		e = &c
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.opts.AuditTimeout)
+	defer cancel()
+	if err := w.send(ctx, NotificationFromAudit(e), w.opts.AuditRetry); err != nil {
+		return NewError("Webhook.Audit", err)
+	}
+	return nil
+}
+
+func (w *Webhook) send(ctx context.Context, n *Notification, retry *RetryPolicy) error {
+	body, err := w.render(n)
+	if err != nil {
+		return err
+	}
+	return retry.do(ctx, func(ctx context.Context) error {
+		return w.post(ctx, body)
+	}, func(int, time.Duration, error) {})
+}
+
+func (w *Webhook) render(n *Notification) ([]byte, error) {
+	if w.tmpl == nil {
+		return json.Marshal(n)
+	}
+
+	var buf bytes.Buffer
+	if err := w.tmpl.Execute(&buf, n); err != nil {
+		return nil, err
+	} else if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("template did not produce valid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, vs := range w.opts.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.opts.Secret) > 0 {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, signWebhook(w.opts.Secret, ts, body))
+	}
+
+	resp, err := w.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	// Retry-Afterを再試行の待ち時間に反映する
+	if x, ok := ctx.Value(exchangeKey{}).(*exchange); ok {
+		x.statusCode = resp.StatusCode
+		x.header = resp.Header
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &WebhookError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// signWebhook タイムスタンプと"."とボディを連結したもののHMAC-SHA256を"sha256="に続けた16進数で返す
+func signWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature Webhookが付けた署名を検証する。maxAgeが正ならタイムスタンプの古さも確かめる
+func VerifyWebhookSignature(secret []byte, header http.Header, body []byte, maxAge time.Duration) bool {
+	ts := header.Get(WebhookTimestampHeader)
+	sig := header.Get(WebhookSignatureHeader)
+	if ts == "" || sig == "" {
+		return false
+	}
+	if maxAge > 0 {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return false
+		}
+		if age := time.Since(time.Unix(sec, 0)); age > maxAge || age < -maxAge {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(sig), []byte(signWebhook(secret, ts, body)))
+}
@@ -0,0 +1,235 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookReceiver responds with the given statuses in order, then 204.
+func newWebhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, func() []receivedWebhook) {
+	var mu sync.Mutex
+	var received []receivedWebhook
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedWebhook{header: r.Header.Clone(), body: body})
+		n := len(received)
+		mu.Unlock()
+
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []receivedWebhook {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedWebhook{}, received...)
+	}
+}
+
+var fastRetry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, StatusCodes: []int{http.StatusServiceUnavailable}}
+
+func TestWebhook_Event(t *testing.T) {
+	assert := require.New(t)
+	srv, received := newWebhookReceiver(t, http.StatusServiceUnavailable)
+	secret := []byte("s3cret")
+
+	hook, err := NewWebhook(WebhookOptions{
+		URL:    srv.URL,
+		Secret: secret,
+		Header: http.Header{"Authorization": {"Bearer token"}},
+		Retry:  fastRetry,
+	})
+	assert.NoError(err)
+
+	ch := make(chan Event, 1)
+	ch <- Event{
+		Type: EventPeerStatusChanged, Resource: "Peer", ID: "router-1", Partition: "hsm-1",
+		Changes: []FieldChange{{Field: "Status", Old: "UP", New: "DOWN"}},
+	}
+	close(ch)
+	NotifyEvents(context.Background(), hook, ch, func(err error) { t.Error(err) })
+
+	got := received()
+	assert.Len(got, 2)
+	req := got[1]
+	assert.Equal("Bearer token", req.header.Get("Authorization"))
+	assert.Equal("application/json", req.header.Get("Content-Type"))
+	assert.True(VerifyWebhookSignature(secret, req.header, req.body, time.Minute))
+	assert.False(VerifyWebhookSignature([]byte("other"), req.header, req.body, time.Minute))
+	assert.False(VerifyWebhookSignature(secret, req.header, append(req.body, ' '), time.Minute))
+
+	var n Notification
+	assert.NoError(json.Unmarshal(req.body, &n))
+	assert.Equal("watcher", n.Source)
+	assert.Equal("PeerStatusChanged", n.Type)
+	assert.Equal("router-1", n.ID)
+	assert.Equal(`Peer router-1 PeerStatusChanged (Status: "UP" -> "DOWN")`, n.Summary)
+}
+
+func TestWebhook_Template(t *testing.T) {
+	assert := require.New(t)
+	srv, received := newWebhookReceiver(t)
+
+	hook, err := NewWebhook(WebhookOptions{URL: srv.URL, Template: SlackTemplate})
+	assert.NoError(err)
+	assert.NoError(hook.Notify(context.Background(), &Notification{Summary: `say "hi"`}))
+	assert.JSONEq(`{"text": "say \"hi\""}`, string(received()[0].body))
+	assert.Empty(received()[0].header.Get(WebhookSignatureHeader))
+
+	hook, err = NewWebhook(WebhookOptions{URL: srv.URL, Template: `{"text": {{.Summary}}}`})
+	assert.NoError(err)
+	assert.Error(hook.Notify(context.Background(), &Notification{Summary: "not json"}))
+	assert.Len(received(), 1)
+
+	_, err = NewWebhook(WebhookOptions{URL: srv.URL, Template: `{{`})
+	assert.Error(err)
+}
+
+func TestWebhook_Failure(t *testing.T) {
+	assert := require.New(t)
+	srv, received := newWebhookReceiver(t, http.StatusBadRequest)
+
+	hook, err := NewWebhook(WebhookOptions{URL: srv.URL, Retry: fastRetry})
+	assert.NoError(err)
+	err = hook.Notify(context.Background(), &Notification{})
+	var werr *WebhookError
+	assert.True(errors.As(err, &werr))
+	assert.Equal(http.StatusBadRequest, werr.StatusCode)
+	assert.Len(received(), 1)
+}
+
+func TestWebhook_Audit(t *testing.T) {
+	assert := require.New(t)
+	srv, received := newWebhookReceiver(t)
+
+	hook, err := NewWebhook(WebhookOptions{URL: srv.URL})
+	assert.NoError(err)
+	client := newAuditedLicenseClient(t, hook)
+	assert.Error(NewLicenseOp(client).Delete(context.Background(), "license-1"))
+
+	got := received()
+	assert.Len(got, 1)
+	var n Notification
+	assert.NoError(json.Unmarshal(got[0].body, &n))
+	assert.Equal("audit", n.Source)
+	assert.Equal("License.Delete", n.Type)
+	assert.Equal("License", n.Resource)
+	assert.Equal("license-1", n.ID)
+	assert.Contains(n.Summary, "alice: License.Delete license-1 failure")
+	assert.Equal(AuditFailure, n.Audit.Outcome)
+}
+
+func TestWebhook_AuditNoRetry(t *testing.T) {
+	assert := require.New(t)
+	srv, received := newWebhookReceiver(t, http.StatusServiceUnavailable)
+
+	// Retry applies to Notify only; audit delivery is not retried by default
+	hook, err := NewWebhook(WebhookOptions{URL: srv.URL, Retry: fastRetry})
+	assert.NoError(err)
+	client := newAuditedLicenseClient(t, hook)
+	_, err = NewLicenseOp(client).Update(context.Background(), "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "after"})
+	assert.NoError(err)
+	assert.Len(received(), 1)
+}
+
+func TestWebhook_AuditTimeout(t *testing.T) {
+	assert := require.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(10 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	hook, err := NewWebhook(WebhookOptions{URL: srv.URL, AuditTimeout: 50 * time.Millisecond})
+	assert.NoError(err)
+	client := newAuditedLicenseClient(t, hook)
+
+	// a hung endpoint must not hold up the operation being audited
+	start := time.Now()
+	_, err = NewLicenseOp(client).Update(context.Background(), "license-1", CloudHSMSoftwareLicenseUpdateParams{Name: "after"})
+	assert.NoError(err)
+	assert.Less(time.Since(start), 5*time.Second)
+}
+
+func TestWebhook_AuditSnapshots(t *testing.T) {
+	assert := require.New(t)
+	newClient := func(hook AuditHook) *v1.Client {
+		return newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hsm := TemplateCloudHSM
+			hsm.SetID("hsm-1")
+			hsm.SetLocalRouter(v1.NewNilCloudHSMLocalRouter(v1.CloudHSMLocalRouter{
+				ResourceID: v1.NewOptString("router-1"),
+				SecretKey:  v1.NewOptString("TOPSECRET"),
+			}))
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSM{CloudHSM: hsm})
+		}), WithAuditHook(hook))
+	}
+	update := func(hook AuditHook) {
+		_, err := NewCloudHSMOp(newClient(hook)).Update(context.Background(), "hsm-1", CloudHSMUpdateParams{Name: "renamed"})
+		assert.NoError(err)
+	}
+
+	// snapshots are left out by default
+	srv, received := newWebhookReceiver(t)
+	hook, err := NewWebhook(WebhookOptions{URL: srv.URL})
+	assert.NoError(err)
+	update(hook)
+	got := received()
+	assert.Len(got, 1)
+	assert.NotContains(string(got[0].body), "TOPSECRET")
+	var n Notification
+	assert.NoError(json.Unmarshal(got[0].body, &n))
+	assert.Equal("hsm-1", n.ID)
+	assert.Empty(n.Audit.Before)
+	assert.Empty(n.Audit.After)
+
+	// when included they still leave the key out
+	srv, received = newWebhookReceiver(t)
+	hook, err = NewWebhook(WebhookOptions{URL: srv.URL, IncludeSnapshots: true})
+	assert.NoError(err)
+	update(hook)
+	got = received()
+	assert.Len(got, 1)
+	assert.NotContains(string(got[0].body), "TOPSECRET")
+	n = Notification{}
+	assert.NoError(json.Unmarshal(got[0].body, &n))
+	assert.Contains(string(n.Audit.Before), `"LocalRouter":{"ResourceID":"router-1"}`)
+	assert.NotContains(string(n.Audit.After), "SecretKey")
+}
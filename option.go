@@ -0,0 +1,223 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ht "github.com/ogen-go/ogen/http"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// ClientOption NewClientやNewClientWithApiUrlに渡すオプション
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	userAgent       string
+	userAgentSuffix string
+	rootURL         string
+	zone            string
+	endpoint        string
+	serviceKey      string
+	security        v1.SecuritySource
+	httpClient      ht.Client
+	retry           *RetryPolicy
+	limiter         *RateLimiter
+	interceptors    []Interceptor
+	logger          *slog.Logger
+	audit           AuditHook
+	actor           func() string
+	protection      *DeletionProtection
+	dryRun          bool
+	cache           *Cache
+	middlewares     []func(ht.Client) ht.Client
+}
+
+func newClientConfig(apiUrl string, opts ...ClientOption) *clientConfig {
+	cfg := &clientConfig{
+		userAgent:  UserAgent,
+		rootURL:    apiUrl,
+		serviceKey: ServiceKey,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.userAgentSuffix != "" {
+		cfg.userAgent += " " + cfg.userAgentSuffix
+	}
+	return cfg
+}
+
+// WithUserAgent APIリクエスト時のユーザーエージェントを指定する
+func WithUserAgent(ua string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.userAgent = ua
+	}
+}
+
+// WithUserAgentSuffix ユーザーエージェントの末尾に付け加える文字列を指定する
+//
+// 同一プロセス内の複数のコンポーネントがそれぞれを名乗るために用いる。
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.userAgentSuffix = suffix
+	}
+}
+
+// WithRootURL APIルートURLを指定する
+//
+// NewClientに渡した場合はゾーンやエンドポイントの指定より優先される。
+func WithRootURL(url string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.rootURL = url
+	}
+}
+
+// WithZone 接続先のゾーンを指定する
+func WithZone(zone string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.zone = zone
+	}
+}
+
+// WithEndpoint 接続先のエンドポイントURLを指定する
+//
+// ゾーンごとのAPIルートURLはこのエンドポイントの下に組み立てられる。
+func WithEndpoint(endpoint string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.endpoint = endpoint
+	}
+}
+
+// WithServiceKey saclientのエンドポイント設定を引く際のサービスキーを指定する
+func WithServiceKey(key string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.serviceKey = key
+	}
+}
+
+// WithSecuritySource 認証情報の供給元を指定する
+//
+// 指定しない場合、認証はsaclient.ClientAPI側に任せる。
+func WithSecuritySource(src v1.SecuritySource) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.security = src
+	}
+}
+
+// WithBasicAuth Basic認証に用いるアクセストークンとシークレットを指定する
+func WithBasicAuth(token, secret string) ClientOption {
+	return WithSecuritySource(basicAuthSource{v1.BasicAuth{Username: token, Password: secret}})
+}
+
+// WithHTTPClient saclient.ClientAPIの代わりに用いるHTTPクライアントを指定する
+//
+// *http.Clientのほか、Do()を持つ任意の実装を渡せる。
+// 認証情報はWithSecuritySourceまたはWithBasicAuthで別途指定する。
+func WithHTTPClient(client ht.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.httpClient = client
+	}
+}
+
+func (cfg *clientConfig) settings() *settings {
+	return &settings{
+		zone:         zoneOf(cfg.rootURL),
+		retry:        cfg.retry,
+		limiter:      cfg.limiter,
+		interceptors: cfg.interceptors,
+		logger:       cfg.logger,
+		audit:        cfg.audit,
+		actor:        cfg.actor,
+		protection:   cfg.protection,
+		dryRun:       cfg.dryRun,
+		cache:        cfg.cache,
+	}
+}
+
+// wrap WithHTTPClientMiddlewareで指定された処理でHTTPクライアントを包む
+func (cfg *clientConfig) wrap(doer ht.Client) ht.Client {
+	if cfg.dryRun {
+		logger := cfg.logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		doer = &dryRunDoer{next: doer, logger: logger}
+	}
+	for i := len(cfg.middlewares) - 1; i >= 0; i-- {
+		doer = cfg.middlewares[i](doer)
+	}
+	return &observingDoer{next: doer}
+}
+
+// zoneOf ".../zone/is1b/api/..."の形をしたAPIルートURLからゾーン名を取り出す
+func zoneOf(rootURL string) string {
+	u, err := url.Parse(rootURL)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "zone" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+func (cfg *clientConfig) securitySource() v1.SecuritySource {
+	if cfg.security == nil {
+		return EmptySecuritySource{}
+	}
+	return cfg.security
+}
+
+func (cfg *clientConfig) newDoer(next ht.Client) ht.Client {
+	return &doer{
+		next:         next,
+		userAgent:    cfg.userAgent,
+		delegateAuth: cfg.security == nil,
+	}
+}
+
+type basicAuthSource struct {
+	auth v1.BasicAuth
+}
+
+func (s basicAuthSource) BasicAuth(context.Context, v1.OperationName) (v1.BasicAuth, error) {
+	return s.auth, nil
+}
+
+// doer saclient.Client以外のHTTPクライアントに、saclient.Clientと同等のヘッダを付与する
+type doer struct {
+	next      ht.Client
+	userAgent string
+
+	// EmptySecuritySourceが付与する空のBasic認証を取り除き、認証をnextに任せる
+	delegateAuth bool
+}
+
+func (d *doer) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", d.userAgent)
+	req.Header.Set("X-Sakura-Bigint-As-Int", "1")
+	if d.delegateAuth {
+		req.Header.Del("Authorization")
+	}
+	return d.next.Do(req)
+}
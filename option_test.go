@@ -0,0 +1,104 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/sacloud/saclient-go"
+	"github.com/stretchr/testify/require"
+)
+
+// wrappedClientAPI is a saclient.ClientAPI that is not *saclient.Client.
+type wrappedClientAPI struct {
+	saclient.ClientAPI
+	called int
+}
+
+func (w *wrappedClientAPI) Do(req *http.Request) (*http.Response, error) {
+	w.called++
+	req.Header.Set("Authorization", "Bearer from-wrapper")
+	return http.DefaultClient.Do(req)
+}
+
+func newHeaderRecordingServer(t *testing.T, got *http.Header) *httptest.Server {
+	sv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v1.PaginatedCloudHSMSoftwareLicenseList{
+			Count:    0,
+			From:     v1.NewOptInt(0),
+			Total:    v1.NewOptInt(0),
+			Licenses: []v1.CloudHSMSoftwareLicense{},
+		})
+	}))
+	t.Cleanup(sv.Close)
+	return sv
+}
+
+func TestNewClientWithApiUrl_OtherClientAPI(t *testing.T) {
+	assert := require.New(t)
+	var got http.Header
+	sv := newHeaderRecordingServer(t, &got)
+
+	wrapper := &wrappedClientAPI{ClientAPI: &theClient}
+	client, err := NewClientWithApiUrl(sv.URL, wrapper, WithUserAgent("my-agent/1.0"))
+	assert.NoError(err)
+
+	_, err = NewLicenseOp(client).List(context.Background())
+	assert.NoError(err)
+	assert.Equal(1, wrapper.called)
+	assert.Equal("my-agent/1.0", got.Get("User-Agent"))
+	assert.Equal("1", got.Get("X-Sakura-Bigint-As-Int"))
+	assert.Equal("Bearer from-wrapper", got.Get("Authorization"))
+}
+
+func TestNewClientWithApiUrl_HTTPClient(t *testing.T) {
+	assert := require.New(t)
+	var got http.Header
+	sv := newHeaderRecordingServer(t, &got)
+
+	client, err := NewClientWithApiUrl(
+		"https://example.com/unused/",
+		nil,
+		WithRootURL(sv.URL),
+		WithHTTPClient(sv.Client()),
+		WithBasicAuth("token", "secret"),
+	)
+	assert.NoError(err)
+
+	_, err = NewLicenseOp(client).List(context.Background())
+	assert.NoError(err)
+
+	req := http.Request{Header: got}
+	user, pass, ok := req.BasicAuth()
+	assert.True(ok)
+	assert.Equal("token", user)
+	assert.Equal("secret", pass)
+	assert.Equal(UserAgent, got.Get("User-Agent"))
+}
+
+func TestNewClientWithApiUrl_NoClient(t *testing.T) {
+	assert := require.New(t)
+	client, err := NewClientWithApiUrl(DefaultAPIRootURL, nil)
+	assert.Nil(client)
+	assert.Error(err)
+}
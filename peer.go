@@ -24,40 +24,69 @@ import (
 )
 
 type PeerAPI interface {
-	List(ctx context.Context) ([]v1.CloudHSMPeer, error)
+	List(ctx context.Context) ([]Peer, error)
 	Create(ctx context.Context, request CloudHSMPeerCreateParams) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id RouterID) error
 }
 
 var _ PeerAPI = (*PeerOp)(nil)
 
 type PeerOp struct {
 	client *v1.Client
-	hsm    *v1.CloudHSM
+	hsm    *Partition
+	s      *settings
 }
 
-func NewPeerOp(client *v1.Client, hsm *v1.CloudHSM) (PeerAPI, error) {
+func NewPeerOp(client *v1.Client, hsm *Partition) (PeerAPI, error) {
 	// The HSM partition has to be "available" before doing anything with its peers.
-	if hsm.GetAvailability() == v1.AvailabilityEnumAvailable {
-		return &PeerOp{
+	if hsm.Available() {
+		op := &PeerOp{
 			client: client,
 			hsm:    hsm,
-		}, nil
+			s:      settingsOf(client),
+		}
+		if c := op.s.cache; c != nil {
+			return &cachedPeerOp{next: op, cache: c, prefix: "peer:" + string(hsm.ID) + ":"}, nil
+		}
+		return op, nil
 	}
 
 	return nil, errors.New("CloudHSM unavailable")
 }
 
-func (op *PeerOp) List(ctx context.Context) ([]v1.CloudHSMPeer, error) {
-	resp, err := op.client.CloudhsmCloudhsmsPeersRetrieve(
-		ctx,
-		v1.CloudhsmCloudhsmsPeersRetrieveParams{
-			ResourceID: op.hsm.GetID(),
-		},
-	)
+func (op *PeerOp) newCall(method string, name v1.OperationName, id RouterID) *Call {
+	return &Call{Operation: name, Method: method, CloudHSMID: string(op.hsm.ID), PeerID: string(id)}
+}
+
+// find 監査記録に残すピアの状態。個別に取得するAPIがないため一覧から探す
+//
+// CloudHSMOp.beforeと同じく生成コードを直接呼ぶ。
+func (op *PeerOp) find(ctx context.Context, id RouterID) *Peer {
+	resp, err := op.client.CloudhsmCloudhsmsPeersRetrieve(ctx, v1.CloudhsmCloudhsmsPeersRetrieveParams{ResourceID: string(op.hsm.ID)})
+	if err != nil {
+		return nil
+	}
+	for _, p := range resp.GetPeers() {
+		if RouterID(p.GetID()) == id {
+			ret := PeerFromCloudHSMPeer(&p)
+			return &ret
+		}
+	}
+	return nil
+}
+
+func (op *PeerOp) List(ctx context.Context) ([]Peer, error) {
+	resp, err := call(ctx, op.s, op.newCall("Peer.List", v1.CloudhsmCloudhsmsPeersRetrieveOperation, ""), func(ctx context.Context) (*v1.CloudHSMPeerList, error) {
+		return op.client.CloudhsmCloudhsmsPeersRetrieve(
+			ctx,
+			v1.CloudhsmCloudhsmsPeersRetrieveParams{
+				ResourceID: string(op.hsm.ID),
+			},
+		)
+	})
 
 	if err == nil {
-		return resp.GetPeers(), nil
+		return convertAll(resp.GetPeers(), PeerFromCloudHSMPeer), nil
 	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); !ok {
 		return nil, NewAPIError("Peer.List", 0, err)
 	} else if e.StatusCode == http.StatusNotFound {
@@ -68,23 +97,27 @@ func (op *PeerOp) List(ctx context.Context) ([]v1.CloudHSMPeer, error) {
 }
 
 type CloudHSMPeerCreateParams struct {
-	RouterID  string
+	RouterID  RouterID
 	SecretKey string
 }
 
 func (op *PeerOp) Create(ctx context.Context, p CloudHSMPeerCreateParams) error {
-	err := op.client.CloudhsmCloudhsmsPeersCreate(
-		ctx,
-		&v1.WrappedCreateCloudHSMPeer{
-			Peer: v1.CreateCloudHSMPeer{
-				ID:        p.RouterID,
-				SecretKey: p.SecretKey,
+	err := op.s.mutate(ctx, op.newCall("Peer.Create", v1.CloudhsmCloudhsmsPeersCreateOperation, p.RouterID), nil, func(ctx context.Context) error {
+		return op.client.CloudhsmCloudhsmsPeersCreate(
+			ctx,
+			&v1.WrappedCreateCloudHSMPeer{
+				Peer: v1.CreateCloudHSMPeer{
+					ID:        string(p.RouterID),
+					SecretKey: p.SecretKey,
+				},
 			},
-		},
-		v1.CloudhsmCloudhsmsPeersCreateParams{
-			ResourceID: op.hsm.GetID(),
-		},
-	)
+			v1.CloudhsmCloudhsmsPeersCreateParams{
+				ResourceID: string(op.hsm.ID),
+			},
+		)
+	}, func() any {
+		return op.find(ctx, p.RouterID)
+	})
 
 	if err == nil {
 		return nil
@@ -97,14 +130,18 @@ func (op *PeerOp) Create(ctx context.Context, p CloudHSMPeerCreateParams) error
 	}
 }
 
-func (op *PeerOp) Delete(ctx context.Context, id string) error {
-	err := op.client.CloudhsmCloudhsmsPeersDestroy(
-		ctx,
-		v1.CloudhsmCloudhsmsPeersDestroyParams{
-			ResourceID: op.hsm.GetID(),
-			PeerID:     id,
-		},
-	)
+func (op *PeerOp) Delete(ctx context.Context, id RouterID) error {
+	err := op.s.mutate(ctx, op.newCall("Peer.Delete", v1.CloudhsmCloudhsmsPeersDestroyOperation, id), func(ctx context.Context) (any, error) {
+		return op.find(ctx, id), nil
+	}, func(ctx context.Context) error {
+		return op.client.CloudhsmCloudhsmsPeersDestroy(
+			ctx,
+			v1.CloudhsmCloudhsmsPeersDestroyParams{
+				ResourceID: string(op.hsm.ID),
+				PeerID:     string(id),
+			},
+		)
+	}, nil)
 
 	if err == nil {
 		return nil
@@ -36,7 +36,7 @@ func TestCloudHSMPeerOp_List(t *testing.T) {
 		Peers: []v1.CloudHSMPeer{TemplateCloudHSMPeer},
 	}
 	client := newTestCloudHSMPeerClient(expected)
-	api, err := NewPeerOp(client, &TemplateCloudHSM)
+	api, err := NewPeerOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 	peers, err := api.List(ctx)
@@ -49,7 +49,7 @@ func TestCloudHSMPeerOp_List(t *testing.T) {
 func TestCloudHSMPeerOp_Create(t *testing.T) {
 	assert := require.New(t)
 	client := newTestCloudHSMPeerClient(nil, http.StatusNoContent)
-	api, err := NewPeerOp(client, &TemplateCloudHSM)
+	api, err := NewPeerOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -64,7 +64,7 @@ func TestCloudHSMPeerOp_Create_422(t *testing.T) {
 	assert := require.New(t)
 	expected := newErrorResponse("Invalid request body.")
 	client := newTestCloudHSMPeerClient(expected, http.StatusUnprocessableEntity)
-	api, err := NewPeerOp(client, &TemplateCloudHSM)
+	api, err := NewPeerOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -76,7 +76,7 @@ func TestCloudHSMPeerOp_Create_422(t *testing.T) {
 func TestCloudHSMPeerOp_Delete(t *testing.T) {
 	assert := require.New(t)
 	client := newTestCloudHSMPeerClient(nil, http.StatusNoContent)
-	api, err := NewPeerOp(client, &TemplateCloudHSM)
+	api, err := NewPeerOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -88,7 +88,7 @@ func TestCloudHSMPeerOp_Delete_400(t *testing.T) {
 	assert := require.New(t)
 	expected := newErrorResponse("Not found")
 	client := newTestCloudHSMPeerClient(expected, http.StatusNotFound)
-	api, err := NewPeerOp(client, &TemplateCloudHSM)
+	api, err := NewPeerOp(client, &TemplatePartition)
 	assert.NoError(err)
 	ctx := context.Background()
 
@@ -104,10 +104,10 @@ func TestCloudHSMPeerIntegrated(t *testing.T) {
 	testutil.PreCheckEnvsFunc("SAKURA_CLOUDHSM_ID")(t)
 
 	ctx := context.Background()
-	hsm, err := NewCloudHSMOp(client).Read(ctx, os.Getenv("SAKURA_CLOUDHSM_ID"))
+	hsm, err := NewCloudHSMOp(client).Read(ctx, PartitionID(os.Getenv("SAKURA_CLOUDHSM_ID")))
 	assert.NoError(err)
 	assert.NotNil(hsm)
-	assert.Equal(v1.AvailabilityEnumAvailable, hsm.GetAvailability())
+	assert.Equal(v1.AvailabilityEnumAvailable, hsm.Availability)
 	api, err := NewPeerOp(client, hsm)
 	assert.NoError(err)
 
@@ -117,9 +117,9 @@ func TestCloudHSMPeerIntegrated(t *testing.T) {
 	peers, err := api.List(ctx)
 	assert.NoError(err)
 	assert.NotNil(peers)
-	existingPeerIDs := []string{}
+	existingPeerIDs := []RouterID{}
 	for _, p := range peers {
-		existingPeerIDs = append(existingPeerIDs, p.GetID())
+		existingPeerIDs = append(existingPeerIDs, p.ID)
 	}
 
 	// Create
@@ -134,13 +134,13 @@ func TestCloudHSMPeerIntegrated(t *testing.T) {
 	assert.NoError(err)
 	assert.NotNil(peers)
 	assert.NotEmpty(peers)
-	newPeerIDs := []string{}
+	newPeerIDs := []RouterID{}
 	for _, p := range peers {
-		newPeerIDs = append(newPeerIDs, p.GetID())
+		newPeerIDs = append(newPeerIDs, p.ID)
 	}
 
 	// find
-	var createdPeerID string
+	var createdPeerID RouterID
 	for _, i := range newPeerIDs {
 		found := false
 		for _, j := range existingPeerIDs {
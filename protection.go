@@ -0,0 +1,170 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// DeletionProtection CloudHSMOp.DeleteとLicenseOp.Deleteを拒否する条件
+type DeletionProtection struct {
+	// Tag このタグが付いたリソースは削除しない。空なら判定しない
+	Tag string
+
+	// MinAge 作成からこの時間が経っていないリソースは削除しない。0なら判定しない
+	MinAge time.Duration
+
+	// RequireEmpty クライアントやピアが残っているパーティションは削除しない
+	//
+	// 利用可能(available)でないパーティションはクライアントやピアを数えられないため、やはり削除しない。
+	RequireEmpty bool
+}
+
+// DefaultDeletionProtection 既定の削除保護の条件
+var DefaultDeletionProtection = DeletionProtection{
+	Tag:          "protected",
+	MinAge:       30 * time.Minute,
+	RequireEmpty: true,
+}
+
+// WithDeletionProtection 削除保護を有効にする
+//
+// 条件に当てはまるリソースの削除はWithForceを指定しない限りProtectedErrorで失敗する。
+func WithDeletionProtection(p DeletionProtection) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.protection = &p
+	}
+}
+
+// DeleteOption CloudHSMOp.DeleteとLicenseOp.Deleteに渡すオプション
+type DeleteOption func(*deleteConfig)
+
+type deleteConfig struct {
+	force bool
+}
+
+func newDeleteConfig(opts ...DeleteOption) *deleteConfig {
+	cfg := &deleteConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithForce 削除保護を無視して削除する
+func WithForce() DeleteOption {
+	return func(cfg *deleteConfig) {
+		cfg.force = true
+	}
+}
+
+// ErrDeletionProtected 削除保護によって削除が拒否されたことを表す
+var ErrDeletionProtected = errors.New("deletion protected")
+
+// ProtectedError 削除保護によって削除が拒否された理由
+type ProtectedError struct {
+	ID      string
+	Reasons []string
+}
+
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("%s is protected: %s", e.ID, strings.Join(e.Reasons, ", "))
+}
+
+func (e *ProtectedError) Is(target error) bool {
+	return target == ErrDeletionProtected
+}
+
+// reasons タグと作成日時による判定
+func (p *DeletionProtection) reasons(tags []string, createdAt time.Time) []string {
+	var ret []string
+	if p.Tag != "" && slices.Contains(tags, p.Tag) {
+		ret = append(ret, fmt.Sprintf("tagged %q", p.Tag))
+	}
+	if !createdAt.IsZero() && p.MinAge > 0 {
+		if age := time.Since(createdAt); age < p.MinAge {
+			ret = append(ret, fmt.Sprintf("created %s ago", age.Round(time.Second)))
+		}
+	}
+	return ret
+}
+
+// protect 削除保護の条件に当てはまればProtectedErrorを返す
+func (op *CloudHSMOp) protect(ctx context.Context, id PartitionID, opts []DeleteOption) error {
+	p := op.s.protection
+	if p == nil || newDeleteConfig(opts...).force {
+		return nil
+	}
+
+	hsm, err := op.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	reasons := p.reasons(hsm.Tags, hsm.CreatedAt)
+	// クライアントやピアは利用可能なパーティションでなければ数えられない。数えられなければ空とはみなさない
+	if p.RequireEmpty && !hsm.Available() {
+		reasons = append(reasons, "cannot verify clients/peers (partition not available)")
+	} else if p.RequireEmpty {
+		clientOp, err := NewClientOp(op.client, hsm)
+		if err != nil {
+			return NewError("CloudHSM.Delete", err)
+		}
+		if clients, err := clientOp.List(ctx); err != nil {
+			return err
+		} else if len(clients) > 0 {
+			reasons = append(reasons, fmt.Sprintf("has %d clients", len(clients)))
+		}
+
+		peerOp, err := NewPeerOp(op.client, hsm)
+		if err != nil {
+			return NewError("CloudHSM.Delete", err)
+		}
+		if peers, err := peerOp.List(ctx); err != nil {
+			return err
+		} else if len(peers) > 0 {
+			reasons = append(reasons, fmt.Sprintf("has %d peers", len(peers)))
+		}
+	}
+
+	if len(reasons) > 0 {
+		return NewError("CloudHSM.Delete", &ProtectedError{ID: string(id), Reasons: reasons})
+	}
+	return nil
+}
+
+// protect 削除保護の条件に当てはまればProtectedErrorを返す
+func (op *LicenseOp) protect(ctx context.Context, id LicenseID, opts []DeleteOption) error {
+	p := op.s.protection
+	if p == nil || newDeleteConfig(opts...).force {
+		return nil
+	}
+
+	lic, err := op.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if reasons := p.reasons(lic.Tags, lic.CreatedAt); len(reasons) > 0 {
+		return NewError("License.Delete", &ProtectedError{ID: string(id), Reasons: reasons})
+	}
+	return nil
+}
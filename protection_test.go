@@ -0,0 +1,140 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+var testProtection = DeletionProtection{
+	Tag:          "protected",
+	MinAge:       time.Hour,
+	RequireEmpty: true,
+}
+
+// newProtectedCloudHSMClient serves hsm together with the given clients and
+// peers, and counts DELETE requests.
+func newProtectedCloudHSMClient(t *testing.T, hsm v1.CloudHSM, clients []v1.CloudHSMClient, peers []v1.CloudHSMPeer, deletes *int) *v1.Client {
+	clients = append([]v1.CloudHSMClient{}, clients...)
+	peers = append([]v1.CloudHSMPeer{}, peers...)
+	return newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case r.Method == http.MethodDelete:
+			*deletes++
+			respondJSON(w, http.StatusNoContent, nil)
+		case strings.HasSuffix(path, "/clients"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMClientList{
+				Count: len(clients), From: v1.NewOptInt(0), Total: v1.NewOptInt(len(clients)), Clients: clients,
+			})
+		case strings.HasSuffix(path, "/peers"):
+			respondJSON(w, http.StatusOK, v1.CloudHSMPeerList{Peers: peers})
+		default:
+			respondJSON(w, http.StatusOK, v1.WrappedCloudHSM{CloudHSM: hsm})
+		}
+	}), WithDeletionProtection(testProtection))
+}
+
+func TestDeletionProtection_CloudHSM(t *testing.T) {
+	old := TemplateCloudHSM
+	old.SetCreatedAt(v1.DateTime(time.Now().Add(-2 * time.Hour).Format(time.RFC3339Nano)))
+
+	tagged := old
+	tagged.SetTags([]string{"protected"})
+
+	fresh := old
+	fresh.SetCreatedAt(v1.DateTime(time.Now().Format(time.RFC3339Nano)))
+
+	precreate := old
+	precreate.SetAvailability(v1.AvailabilityEnumPrecreate)
+
+	cases := []struct {
+		name    string
+		hsm     v1.CloudHSM
+		clients []v1.CloudHSMClient
+		peers   []v1.CloudHSMPeer
+		reason  string
+	}{
+		{name: "tagged", hsm: tagged, reason: `tagged "protected"`},
+		{name: "fresh", hsm: fresh, reason: "created"},
+		{name: "clients", hsm: old, clients: []v1.CloudHSMClient{TemplateCloudHSMClient}, reason: "has 1 clients"},
+		{name: "peers", hsm: old, peers: []v1.CloudHSMPeer{{ID: "router-1", Routes: []string{}}}, reason: "has 1 peers"},
+		// clients and peers cannot be listed, so the partition is not assumed to be empty
+		{name: "not available", hsm: precreate, reason: "partition not available"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := require.New(t)
+			var deletes int
+			client := newProtectedCloudHSMClient(t, tc.hsm, tc.clients, tc.peers, &deletes)
+			api := NewCloudHSMOp(client)
+
+			err := api.Delete(context.Background(), "hsm-1")
+			assert.ErrorIs(err, ErrDeletionProtected)
+			assert.ErrorContains(err, tc.reason)
+
+			var pe *ProtectedError
+			assert.True(errors.As(err, &pe))
+			assert.Equal("hsm-1", pe.ID)
+			assert.Equal(0, deletes)
+
+			assert.NoError(api.Delete(context.Background(), "hsm-1", WithForce()))
+			assert.Equal(1, deletes)
+		})
+	}
+}
+
+func TestDeletionProtection_CloudHSMAllowed(t *testing.T) {
+	assert := require.New(t)
+	hsm := TemplateCloudHSM
+	hsm.SetCreatedAt(v1.DateTime(time.Now().Add(-2 * time.Hour).Format(time.RFC3339Nano)))
+	var deletes int
+	client := newProtectedCloudHSMClient(t, hsm, nil, nil, &deletes)
+
+	assert.NoError(NewCloudHSMOp(client).Delete(context.Background(), "hsm-1"))
+	assert.Equal(1, deletes)
+}
+
+func TestDeletionProtection_License(t *testing.T) {
+	assert := require.New(t)
+	var deletes int
+	lic := TemplateLicense
+	lic.SetTags([]string{"protected"})
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deletes++
+			respondJSON(w, http.StatusNoContent, nil)
+			return
+		}
+		respondJSON(w, http.StatusOK, v1.WrappedCloudHSMSoftwareLicense{License: v1.NewOptCloudHSMSoftwareLicense(lic)})
+	}), WithDeletionProtection(testProtection))
+	api := NewLicenseOp(client)
+
+	err := api.Delete(context.Background(), "license-1")
+	assert.ErrorIs(err, ErrDeletionProtected)
+	assert.Equal(0, deletes)
+
+	assert.NoError(api.Delete(context.Background(), "license-1", WithForce()))
+	assert.Equal(1, deletes)
+}
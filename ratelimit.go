@@ -0,0 +1,100 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter 各Opの呼び出しを制限するトークンバケット
+//
+// 同じRateLimiterをWithRateLimiterで複数のv1.Clientに渡せば、それらの間で上限を共有する。
+// プロセス全体で1つ、あるいはゾーンごとに1つといった使い分けができる。
+type RateLimiter struct {
+	limiter *rate.Limiter
+	weights map[v1.OperationName]int
+
+	mu    sync.Mutex
+	stats map[v1.OperationName]RateLimitStats
+}
+
+// RateLimitStats 操作ごとの待ち時間の統計
+type RateLimitStats struct {
+	// Calls 制限を受けた呼び出しの回数(再試行を含む)
+	Calls int64
+
+	// Waited 待ち時間の合計
+	Waited time.Duration
+
+	// MaxWait 最も長かった待ち時間
+	MaxWait time.Duration
+}
+
+// NewRateLimiter 1秒あたりperSecond個のトークンを補充し、最大burst個まで貯められるRateLimiterを作成する
+//
+// weightsには操作ごとに消費するトークン数を指定する。指定のない操作は1つ消費する。
+func NewRateLimiter(perSecond float64, burst int, weights map[v1.OperationName]int) *RateLimiter {
+	return &RateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(perSecond), burst),
+		weights: maps.Clone(weights),
+		stats:   map[v1.OperationName]RateLimitStats{},
+	}
+}
+
+// WithRateLimiter 各Opの呼び出しにRateLimiterを適用する
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.limiter = l
+	}
+}
+
+// Stats 操作ごとの待ち時間の統計
+func (l *RateLimiter) Stats() map[v1.OperationName]RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return maps.Clone(l.stats)
+}
+
+// Wait 操作に必要なトークンが貯まるまで待つ
+func (l *RateLimiter) Wait(ctx context.Context, name v1.OperationName) error {
+	n := 1
+	if w, ok := l.weights[name]; ok && w > 0 {
+		n = w
+	}
+	n = min(n, l.limiter.Burst())
+
+	start := time.Now()
+	if err := l.limiter.WaitN(ctx, n); err != nil {
+		return err
+	}
+	waited := time.Since(start)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st := l.stats[name]
+	st.Calls++
+	st.Waited += waited
+	st.MaxWait = max(st.MaxWait, waited)
+	l.stats[name] = st
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimitedClient(t *testing.T, l *RateLimiter, resp any) *v1.Client {
+	return newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, resp)
+	}), WithRateLimiter(l))
+}
+
+func TestRateLimiter_SharedAcrossClients(t *testing.T) {
+	assert := require.New(t)
+	l := NewRateLimiter(20, 1, nil)
+	a := newTestLimitedClient(t, l, TemplateWrappedCloudHSM)
+	b := newTestLimitedClient(t, l, TemplateWrappedLicense)
+	ctx := context.Background()
+
+	_, err := NewCloudHSMOp(a).Read(ctx, "1")
+	assert.NoError(err)
+	_, err = NewLicenseOp(b).Read(ctx, "2")
+	assert.NoError(err)
+
+	stats := l.Stats()
+	assert.Equal(int64(1), stats[v1.CloudhsmCloudhsmsRetrieveOperation].Calls)
+	assert.Equal(int64(1), stats[v1.CloudhsmLicensesRetrieveOperation].Calls)
+	// The second call had to wait for the token consumed by the first one.
+	assert.Greater(stats[v1.CloudhsmLicensesRetrieveOperation].Waited, 10*time.Millisecond)
+}
+
+func TestRateLimiter_Weights(t *testing.T) {
+	assert := require.New(t)
+	l := NewRateLimiter(20, 2, map[v1.OperationName]int{
+		v1.CloudhsmCloudhsmsRetrieveOperation: 2,
+	})
+	client := newTestLimitedClient(t, l, TemplateWrappedCloudHSM)
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := NewCloudHSMOp(client).Read(ctx, "1")
+		assert.NoError(err)
+	}
+
+	st := l.Stats()[v1.CloudhsmCloudhsmsRetrieveOperation]
+	assert.Equal(int64(2), st.Calls)
+	// Two tokens at 20/s take roughly 100ms to refill.
+	assert.Greater(st.MaxWait, 50*time.Millisecond)
+}
+
+func TestRateLimiter_ContextCanceled(t *testing.T) {
+	assert := require.New(t)
+	l := NewRateLimiter(0.1, 1, nil)
+	client := newTestLimitedClient(t, l, TemplateWrappedCloudHSM)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := NewCloudHSMOp(client).Read(ctx, "1")
+	assert.NoError(err)
+	_, err = NewCloudHSMOp(client).Read(ctx, "1")
+	assert.Error(err)
+}
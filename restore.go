@@ -0,0 +1,181 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// RestoreOptions Restoreの動作を指定する
+type RestoreOptions struct {
+	// PollInterval 作成したパーティションが利用可能になったかを確かめる間隔。0なら10秒
+	PollInterval time.Duration
+
+	// Peer ピアを作り直す際の引数を返す。falseを返したピアは作り直さない
+	//
+	// SecretKeyはInventoryに含まれず、接続先のルータも移行先では異なるため、呼び出し側で与える。
+	// nilならピアは作り直さない。
+	Peer func(partition *PartitionRecord, peer *PeerRecord) (CloudHSMPeerCreateParams, bool)
+}
+
+// RestoreResult Restoreの結果
+type RestoreResult struct {
+	// 元のIDから作り直したリソースのIDへの対応
+	Partitions map[string]string
+	Clients    map[string]string
+	Peers      map[string]string
+	Licenses   map[string]string
+
+	// Skipped 作り直さなかったリソースとその理由
+	Skipped []string
+}
+
+// Restore Inventoryの内容をclientの接続先(別のアカウントやゾーン)に作り直す
+//
+// パーティションは作成後に利用可能になるのを待ってからクライアントとピアを作成する。
+// 廃止(discontinued)されたパーティションは作り直さない。
+// 途中で失敗した場合は、それまでの結果とともにエラーを返す。
+func Restore(ctx context.Context, client *v1.Client, inv *Inventory, opts RestoreOptions) (*RestoreResult, error) {
+	res := &RestoreResult{
+		Partitions: map[string]string{},
+		Clients:    map[string]string{},
+		Peers:      map[string]string{},
+		Licenses:   map[string]string{},
+	}
+
+	for i := range inv.Partitions {
+		if err := restorePartition(ctx, client, &inv.Partitions[i], opts, res); err != nil {
+			return res, err
+		}
+	}
+
+	licenseOp := NewLicenseOp(client)
+	for _, l := range inv.Licenses {
+		var desc *string
+		if l.Description != "" {
+			desc = &l.Description
+		}
+		created, err := licenseOp.Create(ctx, CloudHSMSoftwareLicenseCreateParams{
+			Name:        l.Name,
+			Description: desc,
+			Tags:        l.Tags,
+		})
+		if err != nil {
+			return res, err
+		}
+		res.Licenses[l.ID] = string(created.ID)
+	}
+
+	return res, nil
+}
+
+func restorePartition(ctx context.Context, client *v1.Client, p *PartitionRecord, opts RestoreOptions, res *RestoreResult) error {
+	if p.Availability == string(v1.AvailabilityEnumDiscontinued) {
+		res.Skipped = append(res.Skipped, fmt.Sprintf("partition %s: discontinued", p.ID))
+		return nil
+	}
+
+	var desc *string
+	if p.Description != "" {
+		desc = &p.Description
+	}
+	hsmOp := NewCloudHSMOp(client)
+	created, err := hsmOp.Create(ctx, CloudHSMCreateParams{
+		Name:               p.Name,
+		Description:        desc,
+		Tags:               p.Tags,
+		Ipv4NetworkAddress: toPrefix(p.Ipv4NetworkAddress, p.Ipv4PrefixLength),
+	})
+	if err != nil {
+		return err
+	}
+	res.Partitions[p.ID] = string(created.ID)
+
+	if len(p.Clients) == 0 && (len(p.Peers) == 0 || opts.Peer == nil) {
+		return nil
+	}
+
+	hsm, err := waitAvailable(ctx, hsmOp, created, opts.PollInterval)
+	if err != nil {
+		return err
+	}
+
+	clientOp, err := NewClientOp(client, hsm)
+	if err != nil {
+		return NewError("Restore", err)
+	}
+	for _, c := range p.Clients {
+		created, err := clientOp.Create(ctx, CloudHSMClientCreateParams{Name: c.Name, Certificate: c.Certificate})
+		if err != nil {
+			return err
+		}
+		res.Clients[c.ID] = string(created.ID)
+	}
+
+	peerOp, err := NewPeerOp(client, hsm)
+	if err != nil {
+		return NewError("Restore", err)
+	}
+	for i := range p.Peers {
+		peer := &p.Peers[i]
+		var params CloudHSMPeerCreateParams
+		var ok bool
+		if opts.Peer != nil {
+			params, ok = opts.Peer(p, peer)
+		}
+		if !ok {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("peer %s of partition %s: no parameters given", peer.ID, p.ID))
+			continue
+		}
+		if err := peerOp.Create(ctx, params); err != nil {
+			return err
+		}
+		res.Peers[peer.ID] = string(params.RouterID)
+	}
+	return nil
+}
+
+// waitAvailable 作成したパーティションが利用可能になるまで待つ
+func waitAvailable(ctx context.Context, op CloudHSMAPI, created *Partition, interval time.Duration) (*Partition, error) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	if created.Available() {
+		return created, nil
+	}
+
+	ctx = BypassCache(ctx)
+	for {
+		hsm, err := op.Read(ctx, created.ID)
+		if err != nil {
+			return nil, err
+		} else if hsm.Available() {
+			return hsm, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, NewError("Restore", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-faster/errors"
+	ogen "github.com/ogen-go/ogen/validate"
+)
+
+// RetryPolicy 一時的な失敗に対する再試行の方針
+//
+// List/Read/Update/Deleteは冪等なので常に再試行の対象となる。
+// Createは重複して作成される恐れがあるため、RetryCreateを指定した場合に限り再試行する。
+type RetryPolicy struct {
+	// MaxAttempts 初回を含めた最大試行回数
+	MaxAttempts int
+
+	// BaseDelay 初回の再試行までの待ち時間。以降は倍々に伸びる。0なら待たずに再試行する
+	BaseDelay time.Duration
+
+	// MaxDelay 待ち時間の上限。Retry-Afterヘッダによる指示にも適用する。0なら上限を設けない
+	MaxDelay time.Duration
+
+	// Jitter 待ち時間をランダムに揺らす割合(0から1)
+	Jitter float64
+
+	// RetryCreate Createも再試行の対象とする
+	RetryCreate bool
+
+	// StatusCodes 再試行の対象とするHTTPステータスコード
+	//
+	// 空ならHTTPステータスコードによる再試行は行わず、接続断やタイムアウトのみを再試行する。
+	StatusCodes []int
+}
+
+// DefaultRetryPolicy 既定の再試行方針
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   1 * time.Second,
+	MaxDelay:    30 * time.Second,
+	Jitter:      0.2,
+	StatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetryPolicy 各Opの呼び出しに再試行の方針を適用する
+//
+// saclient.Clientを用いる場合、二重に再試行しないようsaclient側の再試行は無効化される。
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.retry = &p
+	}
+}
+
+// RetryError 再試行を経ても失敗したことを表す
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %s", e.Attempts, e.Err.Error())
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// do fを再試行しながら呼ぶ。onRetryは再試行の待ちに入る前に呼ばれる
+func (p *RetryPolicy) do(ctx context.Context, f func(context.Context) error, onRetry func(attempt int, delay time.Duration, err error)) error {
+	for attempt := 1; ; attempt++ {
+		var x exchange
+		err := f(context.WithValue(ctx, exchangeKey{}, &x))
+
+		if err == nil {
+			return nil
+		} else if attempt >= p.MaxAttempts || !p.retryable(ctx, err) {
+			return p.giveUp(attempt, err)
+		}
+
+		d := p.delay(attempt, x.header)
+		onRetry(attempt, d, err)
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return p.giveUp(attempt, err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (p *RetryPolicy) giveUp(attempt int, err error) error {
+	if attempt > 1 {
+		return &RetryError{Attempts: attempt, Err: err}
+	}
+	return err
+}
+
+// statusCoder HTTPステータスコードを持つエラー。生成コード以外のHTTP呼び出しもStatusCodesで再試行を判定できる
+type statusCoder interface {
+	error
+	HTTPStatusCode() int
+}
+
+func (p *RetryPolicy) retryable(ctx context.Context, err error) bool {
+	var ne net.Error
+
+	if ctx.Err() != nil {
+		return false
+	} else if e, ok := errors.Into[*ogen.UnexpectedStatusCodeError](err); ok {
+		return slices.Contains(p.StatusCodes, e.StatusCode)
+	} else if e, ok := errors.Into[statusCoder](err); ok {
+		return slices.Contains(p.StatusCodes, e.HTTPStatusCode())
+	} else if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	} else if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	} else if errors.As(err, &ne) {
+		return ne.Timeout()
+	} else {
+		return false
+	}
+}
+
+func (p *RetryPolicy) delay(attempt int, header http.Header) time.Duration {
+	if d, ok := retryAfter(header); ok {
+		return p.limit(d)
+	}
+
+	var d time.Duration
+	if p.BaseDelay > 0 {
+		d = p.BaseDelay << (attempt - 1)
+		if d>>(attempt-1) != p.BaseDelay {
+			// 桁あふれ
+			d = math.MaxInt64
+		}
+	}
+	d = p.limit(d)
+	if p.Jitter > 0 {
+		//nolint:gosec // jitter does not need a cryptographic RNG
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return max(d, 0)
+}
+
+// limit MaxDelayを超えないようにする
+func (p *RetryPolicy) limit(d time.Duration) time.Duration {
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return d
+}
+
+// retryAfter Retry-Afterヘッダ(秒数またはHTTP日付)を解釈する
+func retryAfter(header http.Header) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	} else if sec, err := strconv.Atoi(v); err == nil && sec >= 0 {
+		return time.Duration(sec) * time.Second, true
+	} else if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	} else {
+		return 0, false
+	}
+}
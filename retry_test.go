@@ -0,0 +1,172 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    5 * time.Millisecond,
+	StatusCodes: DefaultRetryPolicy.StatusCodes,
+}
+
+// newFlakyClient returns a client whose server fails with the given status
+// `failures` times, then succeeds with resp.
+func newFlakyClient(t *testing.T, hits *int32, failures int32, status int, resp any, ok int, policy RetryPolicy) *v1.Client {
+	return newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(hits, 1) <= failures {
+			w.Header().Set("Retry-After", "0")
+			respondJSON(w, status, newErrorResponse("try again"))
+			return
+		}
+		respondJSON(w, ok, resp)
+	}), WithRetryPolicy(policy))
+}
+
+func TestRetry_Read(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	client := newFlakyClient(t, &hits, 2, http.StatusServiceUnavailable, TemplateWrappedCloudHSM, http.StatusOK, testRetryPolicy)
+
+	res, err := NewCloudHSMOp(client).Read(context.Background(), "12345")
+	assert.NoError(err)
+	assert.NotNil(res)
+	assert.Equal(int32(3), hits)
+}
+
+func TestRetry_Exhausted(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	client := newFlakyClient(t, &hits, 10, http.StatusTooManyRequests, nil, http.StatusNoContent, testRetryPolicy)
+
+	err := NewLicenseOp(client).Delete(context.Background(), "12345")
+	assert.Error(err)
+	assert.Equal(int32(3), hits)
+
+	var re *RetryError
+	assert.True(errors.As(err, &re))
+	assert.Equal(3, re.Attempts)
+	assert.ErrorContains(err, "giving up after 3 attempts")
+	assert.ErrorContains(err, "429")
+}
+
+func TestRetry_NotTransient(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	client := newFlakyClient(t, &hits, 10, http.StatusUnprocessableEntity, nil, http.StatusOK, testRetryPolicy)
+
+	_, err := NewLicenseOp(client).Update(context.Background(), "12345", CloudHSMSoftwareLicenseUpdateParams{})
+	assert.Error(err)
+	assert.ErrorContains(err, "invalid")
+	assert.Equal(int32(1), hits)
+
+	var re *RetryError
+	assert.False(errors.As(err, &re))
+}
+
+func TestRetry_CreateNotRetriedByDefault(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	client := newFlakyClient(t, &hits, 1, http.StatusServiceUnavailable, TemplateWrappedCreateLicense, http.StatusCreated, testRetryPolicy)
+
+	res, err := NewLicenseOp(client).Create(context.Background(), CloudHSMSoftwareLicenseCreateParams{Name: "x"})
+	assert.Error(err)
+	assert.Nil(res)
+	assert.Equal(int32(1), hits)
+}
+
+func TestRetry_CreateOptIn(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	policy := testRetryPolicy
+	policy.RetryCreate = true
+	client := newFlakyClient(t, &hits, 1, http.StatusBadGateway, TemplateWrappedCreateLicense, http.StatusCreated, policy)
+
+	res, err := NewLicenseOp(client).Create(context.Background(), CloudHSMSoftwareLicenseCreateParams{Name: "x"})
+	assert.NoError(err)
+	assert.NotNil(res)
+	assert.Equal(int32(2), hits)
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	policy := testRetryPolicy
+	policy.MaxAttempts = 10
+	ctx, cancel := context.WithCancel(context.Background())
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		cancel()
+		w.Header().Set("Retry-After", "60")
+		respondJSON(w, http.StatusServiceUnavailable, newErrorResponse("later"))
+	}), WithRetryPolicy(policy))
+
+	_, err := NewCloudHSMOp(client).List(ctx)
+	assert.Error(err)
+	assert.Equal(int32(1), hits)
+}
+
+func TestRetry_ZeroBaseDelay(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	policy := testRetryPolicy
+	policy.BaseDelay = 0
+	policy.MaxDelay = time.Hour
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			respondJSON(w, http.StatusServiceUnavailable, newErrorResponse("try again"))
+			return
+		}
+		respondJSON(w, http.StatusOK, TemplateWrappedCloudHSM)
+	}), WithRetryPolicy(policy))
+
+	// no backoff must not fall back to MaxDelay
+	start := time.Now()
+	_, err := NewCloudHSMOp(client).Read(context.Background(), "12345")
+	assert.NoError(err)
+	assert.Equal(int32(3), hits)
+	assert.Less(time.Since(start), time.Second)
+}
+
+func TestRetry_RetryAfterCapped(t *testing.T) {
+	assert := require.New(t)
+	var hits int32
+	client := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			respondJSON(w, http.StatusServiceUnavailable, newErrorResponse("later"))
+			return
+		}
+		respondJSON(w, http.StatusOK, TemplateWrappedCloudHSM)
+	}), WithRetryPolicy(testRetryPolicy))
+
+	start := time.Now()
+	_, err := NewCloudHSMOp(client).Read(context.Background(), "12345")
+	assert.NoError(err)
+	assert.Equal(int32(2), hits)
+	assert.Less(time.Since(start), time.Second)
+}
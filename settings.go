@@ -0,0 +1,59 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+	"weak"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// settings 各Opの振る舞いを決めるクライアント単位の設定
+//
+// v1.Clientは生成コードなので独自のフィールドを持たせられない。
+// そこでNewClientWithApiUrlが作成したv1.Clientごとにここへ登録し、
+// NewCloudHSMOpなどが同じv1.Clientから引き当てる。
+type settings struct {
+	zone         string
+	retry        *RetryPolicy
+	limiter      *RateLimiter
+	interceptors []Interceptor
+	logger       *slog.Logger
+	audit        AuditHook
+	actor        func() string
+	protection   *DeletionProtection
+	dryRun       bool
+	cache        *Cache
+}
+
+var defaultSettings settings
+
+var registry sync.Map // weak.Pointer[v1.Client] -> *settings
+
+func register(client *v1.Client, s *settings) {
+	key := weak.Make(client)
+	registry.Store(key, s)
+	runtime.AddCleanup(client, func(k weak.Pointer[v1.Client]) { registry.Delete(k) }, key)
+}
+
+func settingsOf(client *v1.Client) *settings {
+	if v, ok := registry.Load(weak.Make(client)); ok {
+		return v.(*settings)
+	}
+	return &defaultSettings
+}
@@ -0,0 +1,303 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// InventoryVersion Inventoryの形式の版。互換性のない変更をしたときに上げる
+const InventoryVersion = 1
+
+// Inventory ある時点でAPIから取得できたリソースの一覧
+//
+// JSONまたはYAMLに書き出して保存し、LoadInventoryで読み戻せる。
+// ピアやLocalRouterのSecretKeyは含まない。
+type Inventory struct {
+	// Version 形式の版。InventoryVersion
+	Version int `json:"version" yaml:"version"`
+
+	// TakenAt 取得を始めた時刻
+	TakenAt time.Time `json:"taken_at" yaml:"taken_at"`
+
+	// Zone 取得元のゾーン。APIルートURLから判別できない場合は空
+	Zone string `json:"zone,omitempty" yaml:"zone,omitempty"`
+
+	Partitions []PartitionRecord `json:"partitions" yaml:"partitions"`
+	Licenses   []LicenseRecord   `json:"licenses" yaml:"licenses"`
+}
+
+// PartitionRecord パーティションとそれに属するピア・クライアント
+type PartitionRecord struct {
+	ID                 string   `json:"id" yaml:"id"`
+	Name               string   `json:"name" yaml:"name"`
+	Description        string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags               []string `json:"tags" yaml:"tags"`
+	Availability       string   `json:"availability" yaml:"availability"`
+	Ipv4NetworkAddress string   `json:"ipv4_network_address" yaml:"ipv4_network_address"`
+	Ipv4PrefixLength   int      `json:"ipv4_prefix_length" yaml:"ipv4_prefix_length"`
+	Ipv4Address        string   `json:"ipv4_address,omitempty" yaml:"ipv4_address,omitempty"`
+	CreatedAt          string   `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	ModifiedAt         string   `json:"modified_at,omitempty" yaml:"modified_at,omitempty"`
+
+	// LocalRouter パーティションに接続されたローカルルータ。なければnil
+	LocalRouter *LocalRouterRecord `json:"local_router,omitempty" yaml:"local_router,omitempty"`
+
+	// Peers、Clients パーティションが利用可能(available)でなければ取得できないので空
+	Peers   []PeerRecord   `json:"peers" yaml:"peers"`
+	Clients []ClientRecord `json:"clients" yaml:"clients"`
+}
+
+// LocalRouterRecord ローカルルータ
+type LocalRouterRecord struct {
+	ResourceID string `json:"resource_id" yaml:"resource_id"`
+}
+
+// PeerRecord ピア
+type PeerRecord struct {
+	ID     string   `json:"id" yaml:"id"`
+	Index  *int     `json:"index,omitempty" yaml:"index,omitempty"`
+	Status string   `json:"status,omitempty" yaml:"status,omitempty"`
+	Routes []string `json:"routes" yaml:"routes"`
+}
+
+// ClientRecord クライアントとその証明書
+type ClientRecord struct {
+	ID           string `json:"id" yaml:"id"`
+	Name         string `json:"name" yaml:"name"`
+	Availability string `json:"availability" yaml:"availability"`
+	Certificate  string `json:"certificate" yaml:"certificate"`
+	CreatedAt    string `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	ModifiedAt   string `json:"modified_at,omitempty" yaml:"modified_at,omitempty"`
+}
+
+// LicenseRecord ソフトウェアライセンス
+type LicenseRecord struct {
+	ID           string   `json:"id" yaml:"id"`
+	Name         string   `json:"name" yaml:"name"`
+	Description  string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags         []string `json:"tags" yaml:"tags"`
+	ServiceClass string   `json:"service_class" yaml:"service_class"`
+	CreatedAt    string   `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	ModifiedAt   string   `json:"modified_at,omitempty" yaml:"modified_at,omitempty"`
+}
+
+// cloneInventory 呼び出し側の変更が及ばないよう、invを複製する
+func cloneInventory(inv *Inventory) *Inventory {
+	return cloneValue(inv, func(inv Inventory) Inventory {
+		inv.Partitions = cloneList(inv.Partitions, func(p PartitionRecord) PartitionRecord {
+			p.Tags = slices.Clone(p.Tags)
+			p.LocalRouter = clonePtr(p.LocalRouter)
+			p.Peers = cloneList(p.Peers, func(r PeerRecord) PeerRecord {
+				r.Index = clonePtr(r.Index)
+				r.Routes = slices.Clone(r.Routes)
+				return r
+			})
+			p.Clients = slices.Clone(p.Clients)
+			return p
+		})
+		inv.Licenses = cloneList(inv.Licenses, func(l LicenseRecord) LicenseRecord {
+			l.Tags = slices.Clone(l.Tags)
+			return l
+		})
+		return inv
+	})
+}
+
+// Snapshot clientから見えるパーティション・ピア・クライアント・ライセンスをすべて取得する
+func Snapshot(ctx context.Context, client *v1.Client) (*Inventory, error) {
+	return snapshot(ctx, client, true)
+}
+
+// snapshot licensesがfalseならライセンスを取得しない
+func snapshot(ctx context.Context, client *v1.Client, licenses bool) (*Inventory, error) {
+	ctx = BypassCache(ctx)
+	inv := &Inventory{
+		Version: InventoryVersion,
+		TakenAt: time.Now().UTC(),
+		Zone:    settingsOf(client).zone,
+	}
+
+	hsms, err := NewCloudHSMOp(client).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inv.Partitions = make([]PartitionRecord, 0, len(hsms))
+	for i := range hsms {
+		rec, err := snapshotPartition(ctx, client, &hsms[i])
+		if err != nil {
+			return nil, err
+		}
+		inv.Partitions = append(inv.Partitions, *rec)
+	}
+
+	inv.Licenses = []LicenseRecord{}
+	if !licenses {
+		return inv, nil
+	}
+	list, err := NewLicenseOp(client).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range list {
+		inv.Licenses = append(inv.Licenses, LicenseRecord{
+			ID:           string(l.ID),
+			Name:         l.Name,
+			Description:  deref(l.Description),
+			Tags:         nonNil(l.Tags),
+			ServiceClass: string(l.ServiceClass),
+			CreatedAt:    formatTime(l.CreatedAt),
+			ModifiedAt:   formatTime(l.ModifiedAt),
+		})
+	}
+
+	return inv, nil
+}
+
+func snapshotPartition(ctx context.Context, client *v1.Client, hsm *Partition) (*PartitionRecord, error) {
+	rec := &PartitionRecord{
+		ID:           string(hsm.ID),
+		Name:         hsm.Name,
+		Description:  deref(hsm.Description),
+		Tags:         nonNil(hsm.Tags),
+		Availability: string(hsm.Availability),
+		CreatedAt:    formatTime(hsm.CreatedAt),
+		ModifiedAt:   formatTime(hsm.ModifiedAt),
+		Peers:        []PeerRecord{},
+		Clients:      []ClientRecord{},
+	}
+	if n := hsm.Ipv4NetworkAddress; n.IsValid() {
+		rec.Ipv4NetworkAddress = n.Addr().String()
+		rec.Ipv4PrefixLength = n.Bits()
+	}
+	if a := hsm.Ipv4Address; a.IsValid() {
+		rec.Ipv4Address = a.String()
+	}
+	if r := hsm.LocalRouter; r != nil && r.ResourceID != "" {
+		rec.LocalRouter = &LocalRouterRecord{ResourceID: string(r.ResourceID)}
+	}
+
+	if !hsm.Available() {
+		return rec, nil
+	}
+
+	peerOp, err := NewPeerOp(client, hsm)
+	if err != nil {
+		return nil, NewError("Snapshot", err)
+	}
+	peers, err := peerOp.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range peers {
+		pr := PeerRecord{
+			ID:     string(p.ID),
+			Index:  p.Index,
+			Status: string(p.Status),
+			Routes: nonNil(p.Routes),
+		}
+		rec.Peers = append(rec.Peers, pr)
+	}
+
+	clientOp, err := NewClientOp(client, hsm)
+	if err != nil {
+		return nil, NewError("Snapshot", err)
+	}
+	clients, err := clientOp.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range clients {
+		rec.Clients = append(rec.Clients, ClientRecord{
+			ID:           string(c.ID),
+			Name:         c.Name,
+			Availability: string(c.Availability),
+			Certificate:  c.Certificate,
+			CreatedAt:    formatTime(c.CreatedAt),
+			ModifiedAt:   formatTime(c.ModifiedAt),
+		})
+	}
+	return rec, nil
+}
+
+// formatTime ゼロ値なら空文字列
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// WriteJSON インデント付きのJSONとして書き出す
+func (inv *Inventory) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(inv); err != nil {
+		return NewError("Inventory.WriteJSON", err)
+	}
+	return nil
+}
+
+// WriteYAML YAMLとして書き出す
+func (inv *Inventory) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(inv); err != nil {
+		return NewError("Inventory.WriteYAML", err)
+	}
+	if err := enc.Close(); err != nil {
+		return NewError("Inventory.WriteYAML", err)
+	}
+	return nil
+}
+
+// LoadInventory WriteJSONまたはWriteYAMLで書き出したものを読み込む
+//
+// 形式は内容から判別する。新しい版の形式は読み込めない。
+func LoadInventory(r io.Reader) (*Inventory, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, NewError("LoadInventory", err)
+	}
+
+	var inv Inventory
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(b, &inv)
+	} else {
+		err = yaml.Unmarshal(b, &inv)
+	}
+	if err != nil {
+		return nil, NewError("LoadInventory", err)
+	} else if inv.Version < 1 || inv.Version > InventoryVersion {
+		return nil, NewError("LoadInventory", fmt.Errorf("unsupported inventory version %d", inv.Version))
+	}
+	return &inv, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// inventoryHandler serves a fixed set of partitions, peers, clients and licenses.
+func inventoryHandler(t *testing.T) http.Handler {
+	available := TemplateCloudHSM
+	available.SetID("hsm-1")
+	available.SetLocalRouter(v1.NewNilCloudHSMLocalRouter(v1.CloudHSMLocalRouter{
+		ResourceID: v1.NewOptString("router-0"),
+		SecretKey:  v1.NewOptString("router-secret"),
+	}))
+	pending := TemplateCloudHSM
+	pending.SetID("hsm-2")
+	pending.SetAvailability(v1.AvailabilityEnumPrecreate)
+	pending.SetLocalRouter(v1.NilCloudHSMLocalRouter{Null: true})
+
+	c := TemplateCloudHSMClient
+	c.SetID("client-1")
+	c.SetCertificate("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----")
+
+	lic := TemplateLicense
+	lic.SetID("license-1")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := strings.TrimSuffix(r.URL.Path, "/"); {
+		case strings.HasSuffix(p, "/peers"):
+			respondJSON(w, http.StatusOK, v1.CloudHSMPeerList{Peers: []v1.CloudHSMPeer{{
+				ID: "router-1", Index: v1.NewOptInt(0), Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusUP), Routes: []string{"10.0.0.0/24"},
+			}}})
+		case strings.HasSuffix(p, "/clients"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMClientList{
+				Count: 1, From: v1.NewOptInt(0), Total: v1.NewOptInt(1), Clients: []v1.CloudHSMClient{c},
+			})
+		case strings.HasSuffix(p, "/licenses"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMSoftwareLicenseList{
+				Count: 1, From: v1.NewOptInt(0), Total: v1.NewOptInt(1), Licenses: []v1.CloudHSMSoftwareLicense{lic},
+			})
+		case strings.HasSuffix(p, "/cloudhsms"):
+			respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMList{
+				Count: 2, From: v1.NewOptInt(0), Total: v1.NewOptInt(2), CloudHSMs: []v1.CloudHSM{available, pending},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+		}
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	assert := require.New(t)
+	client := newTestClientWithHandler(t, inventoryHandler(t))
+
+	inv, err := Snapshot(context.Background(), client)
+	assert.NoError(err)
+	assert.Equal(InventoryVersion, inv.Version)
+	assert.WithinDuration(time.Now(), inv.TakenAt, time.Minute)
+
+	assert.Len(inv.Partitions, 2)
+	p := inv.Partitions[0]
+	assert.Equal("hsm-1", p.ID)
+	assert.Equal(&LocalRouterRecord{ResourceID: "router-0"}, p.LocalRouter)
+	assert.Equal([]PeerRecord{{ID: "router-1", Index: ref(0), Status: "UP", Routes: []string{"10.0.0.0/24"}}}, p.Peers)
+	assert.Len(p.Clients, 1)
+	assert.Contains(p.Clients[0].Certificate, "BEGIN CERTIFICATE")
+
+	assert.Equal("hsm-2", inv.Partitions[1].ID)
+	assert.Nil(inv.Partitions[1].LocalRouter)
+	assert.Empty(inv.Partitions[1].Peers)
+
+	assert.Len(inv.Licenses, 1)
+	assert.Equal("license-1", inv.Licenses[0].ID)
+
+	for _, write := range []func(io.Writer) error{inv.WriteJSON, inv.WriteYAML} {
+		var buf bytes.Buffer
+		assert.NoError(write(&buf))
+		assert.NotContains(buf.String(), "router-secret")
+
+		loaded, err := LoadInventory(&buf)
+		assert.NoError(err)
+		assert.True(inv.TakenAt.Equal(loaded.TakenAt))
+		loaded.TakenAt = inv.TakenAt
+		assert.Equal(inv, loaded)
+	}
+}
+
+func TestLoadInventory_Version(t *testing.T) {
+	_, err := LoadInventory(strings.NewReader(`{"version": 99, "partitions": [], "licenses": []}`))
+	require.ErrorContains(t, err, "unsupported inventory version 99")
+
+	_, err = LoadInventory(strings.NewReader("partitions: []\n"))
+	require.ErrorContains(t, err, "unsupported inventory version 0")
+}
+
+func TestRestore(t *testing.T) {
+	assert := require.New(t)
+	inv, err := Snapshot(context.Background(), newTestClientWithHandler(t, inventoryHandler(t)))
+	assert.NoError(err)
+
+	var mu sync.Mutex
+	var posted []string
+	target := newTestClientWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		p := strings.TrimSuffix(r.URL.Path, "/")
+		posted = append(posted, p[strings.LastIndex(p, "/")+1:])
+		if strings.HasSuffix(p, "/peers") {
+			respondJSON(w, http.StatusNoContent, nil)
+			return
+		}
+
+		// Echo back the request with a fresh ID.
+		var v map[string]map[string]any
+		if err := json.Unmarshal(body, &v); err != nil {
+			respondJSON(w, http.StatusBadRequest, newErrorResponse(err.Error()))
+			return
+		}
+		for _, obj := range v {
+			obj["ID"] = "new-" + obj["Name"].(string)
+			obj["Availability"] = "available"
+		}
+		respondJSON(w, http.StatusCreated, v)
+	}))
+
+	res, err := Restore(context.Background(), target, inv, RestoreOptions{
+		Peer: func(_ *PartitionRecord, peer *PeerRecord) (CloudHSMPeerCreateParams, bool) {
+			return CloudHSMPeerCreateParams{RouterID: RouterID("moved-" + peer.ID), SecretKey: "s"}, true
+		},
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"cloudhsms", "clients", "peers", "cloudhsms", "licenses"}, posted)
+	assert.Len(res.Partitions, 2)
+	assert.Equal(map[string]string{"router-1": "moved-router-1"}, res.Peers)
+	assert.Len(res.Clients, 1)
+	assert.Len(res.Licenses, 1)
+	assert.Empty(res.Skipped)
+}
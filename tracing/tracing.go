@@ -0,0 +1,197 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing クラウドHSM APIの呼び出しをOpenTelemetryで計装する
+//
+// 各Opのメソッド呼び出し("CloudHSM.Create"など)ごとにスパンを作り、
+// その下にHTTPリクエストごとのスパンをぶら下げる。
+// 証明書やピアのSecretKeyといったリクエストボディの内容は属性に含めない。
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	ht "github.com/ogen-go/ogen/http"
+	cloudhsm "github.com/sacloud/cloudhsm-api-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName 計装スコープ名
+const ScopeName = "github.com/sacloud/cloudhsm-api-go/tracing"
+
+// 属性のキー
+const (
+	AttrZone       = attribute.Key("cloudhsm.zone")
+	AttrOperation  = attribute.Key("cloudhsm.operation")
+	AttrCloudHSMID = attribute.Key("cloudhsm.partition.id")
+	AttrClientID   = attribute.Key("cloudhsm.client.id")
+	AttrPeerID     = attribute.Key("cloudhsm.peer.id")
+	AttrLicenseID  = attribute.Key("cloudhsm.license.id")
+	AttrAttempts   = attribute.Key("cloudhsm.attempts")
+
+	AttrHTTPMethod     = attribute.Key("http.request.method")
+	AttrHTTPStatusCode = attribute.Key("http.response.status_code")
+	AttrURL            = attribute.Key("url.full")
+	AttrServerAddress  = attribute.Key("server.address")
+)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// Option 計装のオプション
+type Option func(*config)
+
+// WithTracerProvider スパンの作成に用いるTracerProviderを指定する。既定はotel.GetTracerProvider()
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(cfg *config) {
+		cfg.provider = tp
+	}
+}
+
+// WithPropagator HTTPヘッダへのコンテキスト伝播に用いるPropagatorを指定する。既定はotel.GetTextMapPropagator()
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(cfg *config) {
+		cfg.propagator = p
+	}
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func (cfg *config) tracer() trace.Tracer {
+	return cfg.provider.Tracer(ScopeName, trace.WithInstrumentationVersion(cloudhsm.Version))
+}
+
+// ClientOptions cloudhsm.NewClientなどに渡して、メソッド呼び出しとHTTPリクエストの両方を計装する
+func ClientOptions(opts ...Option) []cloudhsm.ClientOption {
+	return []cloudhsm.ClientOption{
+		cloudhsm.WithInterceptor(Interceptor(opts...)),
+		cloudhsm.WithHTTPClientMiddleware(Middleware(opts...)),
+	}
+}
+
+// Interceptor 各Opのメソッド呼び出しごとにスパンを作成する
+func Interceptor(opts ...Option) cloudhsm.Interceptor {
+	tracer := newConfig(opts).tracer()
+
+	return func(ctx context.Context, c *cloudhsm.Call, next func(context.Context) error) error {
+		ctx, span := tracer.Start(ctx, c.Method, trace.WithAttributes(callAttributes(c)...))
+		defer span.End()
+
+		err := next(ctx)
+
+		// Createでは作成したIDが呼び出しの後でCallに記録される
+		span.SetAttributes(callAttributes(c)...)
+		span.SetAttributes(AttrAttempts.Int(c.Attempts))
+		if c.StatusCode != 0 {
+			span.SetAttributes(AttrHTTPStatusCode.Int(c.StatusCode))
+		}
+		if err != nil {
+			recordError(span, err)
+		}
+		return err
+	}
+}
+
+// recordError 証明書やSecretKeyを伏せたうえでエラーを記録する
+func recordError(span trace.Span, err error) {
+	msg := cloudhsm.Redact(err.Error())
+	span.RecordError(errors.New(msg))
+	span.SetStatus(codes.Error, msg)
+}
+
+func callAttributes(c *cloudhsm.Call) []attribute.KeyValue {
+	ret := []attribute.KeyValue{AttrOperation.String(c.Operation)}
+	for _, i := range []struct {
+		key attribute.Key
+		val string
+	}{
+		{AttrZone, c.Zone},
+		{AttrCloudHSMID, c.CloudHSMID},
+		{AttrClientID, c.ClientID},
+		{AttrPeerID, c.PeerID},
+		{AttrLicenseID, c.LicenseID},
+	} {
+		if i.val != "" {
+			ret = append(ret, i.key.String(i.val))
+		}
+	}
+	return ret
+}
+
+// Middleware HTTPリクエストごとにスパンを作成する
+func Middleware(opts ...Option) func(ht.Client) ht.Client {
+	cfg := newConfig(opts)
+	tracer := cfg.tracer()
+
+	return func(next ht.Client) ht.Client {
+		return &doer{next: next, tracer: tracer, propagator: cfg.propagator}
+	}
+}
+
+type doer struct {
+	next       ht.Client
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func (d *doer) Do(req *http.Request) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		AttrHTTPMethod.String(req.Method),
+		AttrURL.String(req.URL.Redacted()),
+		AttrServerAddress.String(req.URL.Hostname()),
+	}
+	if c, ok := cloudhsm.CallFromContext(req.Context()); ok {
+		attrs = append(attrs, callAttributes(c)...)
+	}
+
+	ctx, span := d.tracer.Start(
+		req.Context(),
+		"HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	d.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.next.Do(req)
+	if err != nil {
+		recordError(span, err)
+		return resp, err
+	}
+
+	span.SetAttributes(AttrHTTPStatusCode.Int(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ht "github.com/ogen-go/ogen/http"
+	cloudhsm "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/sacloud/cloudhsm-api-go/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedClient(t *testing.T, status int, body any) (*v1.Client, *tracetest.SpanRecorder) {
+	sv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if body != nil {
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	}))
+	t.Cleanup(sv.Close)
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+
+	opts := append(
+		tracing.ClientOptions(tracing.WithTracerProvider(tp)),
+		cloudhsm.WithHTTPClient(sv.Client()),
+		cloudhsm.WithBasicAuth("token", "secret"),
+	)
+	client, err := cloudhsm.NewClientWithApiUrl(sv.URL+"/cloud/zone/is1a/api/cloud/1.1/", nil, opts...)
+	require.NoError(t, err)
+	return client, rec
+}
+
+func attrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	ret := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes() {
+		ret[kv.Key] = kv.Value
+	}
+	return ret
+}
+
+func availableHSM() *cloudhsm.Partition {
+	return &cloudhsm.Partition{ID: "hsm-1", Availability: v1.AvailabilityEnumAvailable}
+}
+
+func TestInterceptor_Success(t *testing.T) {
+	assert := require.New(t)
+	resp := v1.CloudHSMPeerList{Peers: []v1.CloudHSMPeer{}}
+	client, rec := newTracedClient(t, http.StatusOK, resp)
+
+	api, err := cloudhsm.NewPeerOp(client, availableHSM())
+	assert.NoError(err)
+	_, err = api.List(context.Background())
+	assert.NoError(err)
+
+	spans := rec.Ended()
+	assert.Len(spans, 2)
+	httpSpan, opSpan := spans[0], spans[1]
+
+	assert.Equal("Peer.List", opSpan.Name())
+	assert.Equal(opSpan.SpanContext().SpanID(), httpSpan.Parent().SpanID())
+
+	a := attrs(opSpan)
+	assert.Equal("is1a", a[tracing.AttrZone].AsString())
+	assert.Equal("hsm-1", a[tracing.AttrCloudHSMID].AsString())
+	assert.Equal(v1.CloudhsmCloudhsmsPeersRetrieveOperation, a[tracing.AttrOperation].AsString())
+	assert.Equal(int64(http.StatusOK), a[tracing.AttrHTTPStatusCode].AsInt64())
+	assert.Equal(codes.Unset, opSpan.Status().Code)
+
+	assert.Equal("HTTP GET", httpSpan.Name())
+	assert.Equal(int64(http.StatusOK), attrs(httpSpan)[tracing.AttrHTTPStatusCode].AsInt64())
+}
+
+func TestInterceptor_Error(t *testing.T) {
+	assert := require.New(t)
+	client, rec := newTracedClient(t, http.StatusNotFound, map[string]any{"is_ok": false})
+
+	err := cloudhsm.NewCloudHSMOp(client).Delete(context.Background(), "hsm-9")
+	assert.Error(err)
+
+	spans := rec.Ended()
+	assert.Len(spans, 2)
+	opSpan := spans[1]
+	assert.Equal("CloudHSM.Delete", opSpan.Name())
+	assert.Equal(codes.Error, opSpan.Status().Code)
+	assert.Equal("hsm-9", attrs(opSpan)[tracing.AttrCloudHSMID].AsString())
+	assert.Equal(int64(http.StatusNotFound), attrs(opSpan)[tracing.AttrHTTPStatusCode].AsInt64())
+	assert.NotEmpty(opSpan.Events())
+}
+
+func TestInterceptor_NoSecrets(t *testing.T) {
+	assert := require.New(t)
+	client, rec := newTracedClient(t, http.StatusNoContent, nil)
+
+	api, err := cloudhsm.NewPeerOp(client, availableHSM())
+	assert.NoError(err)
+	err = api.Create(context.Background(), cloudhsm.CloudHSMPeerCreateParams{
+		RouterID:  "router-1",
+		SecretKey: "very-secret-pairing-key",
+	})
+	assert.NoError(err)
+
+	for _, s := range rec.Ended() {
+		for _, kv := range s.Attributes() {
+			assert.False(strings.Contains(kv.Value.Emit(), "very-secret-pairing-key"), "span %s leaks %s", s.Name(), kv.Key)
+		}
+	}
+	assert.Equal("router-1", attrs(rec.Ended()[1])[tracing.AttrPeerID].AsString())
+}
+
+func TestInterceptor_CreatedID(t *testing.T) {
+	assert := require.New(t)
+	var lic v1.CreateCloudHSMSoftwareLicense
+	lic.SetFake()
+	lic.SetID("license-1")
+	lic.SetTags([]string{})
+	client, rec := newTracedClient(t, http.StatusCreated, v1.WrappedCreateCloudHSMSoftwareLicense{License: v1.NewOptCreateCloudHSMSoftwareLicense(lic)})
+
+	_, err := cloudhsm.NewLicenseOp(client).Create(context.Background(), cloudhsm.CloudHSMSoftwareLicenseCreateParams{Name: "new"})
+	assert.NoError(err)
+
+	opSpan := rec.Ended()[1]
+	assert.Equal("License.Create", opSpan.Name())
+	assert.Equal("license-1", attrs(opSpan)[tracing.AttrLicenseID].AsString())
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestInterceptor_ErrorRedacted(t *testing.T) {
+	assert := require.New(t)
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	leak := func(ht.Client) ht.Client {
+		return doerFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New(`rejected {"SecretKey":"pairing-secret","Certificate":"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"}`)
+		})
+	}
+	opts := append(tracing.ClientOptions(tracing.WithTracerProvider(tp)), cloudhsm.WithHTTPClient(http.DefaultClient), cloudhsm.WithHTTPClientMiddleware(leak))
+	client, err := cloudhsm.NewClientWithApiUrl("http://127.0.0.1/cloud/zone/is1a/api/cloud/1.1/", nil, opts...)
+	assert.NoError(err)
+
+	api, err := cloudhsm.NewPeerOp(client, availableHSM())
+	assert.NoError(err)
+	err = api.Create(context.Background(), cloudhsm.CloudHSMPeerCreateParams{RouterID: "router-1", SecretKey: "pairing-secret"})
+	assert.Error(err)
+
+	spans := rec.Ended()
+	assert.NotEmpty(spans)
+	for _, s := range spans {
+		assert.Equal(codes.Error, s.Status().Code)
+		assert.NotContains(s.Status().Description, "pairing-secret")
+		assert.NotContains(s.Status().Description, "MIIB")
+		for _, e := range s.Events() {
+			for _, kv := range e.Attributes {
+				assert.NotContains(kv.Value.Emit(), "pairing-secret", "span %s leaks %s", s.Name(), kv.Key)
+				assert.NotContains(kv.Value.Emit(), "MIIB", "span %s leaks %s", s.Name(), kv.Key)
+			}
+		}
+	}
+}
@@ -14,6 +14,12 @@
 
 package cloudhsm
 
+import (
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
 // generic-ish type cast helper function
 func intoOpt[T, U any, P interface {
 	*T
@@ -28,3 +34,18 @@ func intoOpt[T, U any, P interface {
 	}
 	return opt
 }
+
+// parseDateTime APIが返すISO 8601形式の日時を解釈する
+func parseDateTime(v v1.DateTime) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339Nano, string(v))
+	return t, err == nil
+}
+
+// deref nilならゼロ値を返す
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
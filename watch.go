@@ -0,0 +1,234 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+)
+
+// EventType Watcherが通知する変化の種類
+type EventType string
+
+const (
+	// EventCreated リソースが作成された
+	EventCreated EventType = "Created"
+
+	// EventUpdated 名前やタグなど、利用可否・状態以外の項目が変わった
+	EventUpdated EventType = "Updated"
+
+	// EventDeleted リソースが削除された
+	EventDeleted EventType = "Deleted"
+
+	// EventAvailabilityChanged パーティションかクライアントのAvailabilityが変わった
+	EventAvailabilityChanged EventType = "AvailabilityChanged"
+
+	// EventPeerStatusChanged ピアのStatusが変わった
+	EventPeerStatusChanged EventType = "PeerStatusChanged"
+)
+
+// Event Watcherが通知する変化
+type Event struct {
+	Type EventType `json:"type"`
+
+	// Resource "Partition"、"Client"、"Peer"のいずれか
+	Resource string `json:"resource"`
+
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Partition クライアントとピアの属するパーティションのID
+	Partition string `json:"partition,omitempty"`
+
+	// Changes EventUpdated、EventAvailabilityChanged、EventPeerStatusChangedの場合に変化した項目
+	Changes []FieldChange `json:"changes,omitempty"`
+
+	// Time 変化を検出した時刻
+	Time time.Time `json:"time"`
+}
+
+// WatcherOptions Watcherの動作を指定する
+type WatcherOptions struct {
+	// Interval 一覧を取得する間隔。0なら30秒
+	Interval time.Duration
+
+	// ErrorInterval 取得に失敗した後、次に取得するまでの間隔。0ならIntervalと同じ
+	ErrorInterval time.Duration
+
+	// State 前回のWatcher.Stateの値。指定すると、最初の取得時にその状態からの変化を通知する。
+	// nilなら最初の取得結果を起点とし、その時点では何も通知しない。
+	// Snapshotの結果も渡せる。Watcherはライセンスを監視しないため、含まれるライセンスはそのまま引き継ぐ
+	State *Inventory
+
+	// Buffer イベントのチャネルのバッファの大きさ
+	Buffer int
+
+	// OnError 取得に失敗したときに呼ばれる。nilなら無視して次の取得を待つ
+	OnError func(error)
+}
+
+// Watcher パーティションとそのクライアント・ピアを定期的に取得し、変化をEventとして通知する
+type Watcher struct {
+	client *v1.Client
+	opts   WatcherOptions
+
+	mu    sync.Mutex
+	state *Inventory
+}
+
+// NewWatcher Watcherを作成する
+func NewWatcher(client *v1.Client, opts WatcherOptions) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.ErrorInterval <= 0 {
+		opts.ErrorInterval = opts.Interval
+	}
+	return &Watcher{client: client, opts: opts, state: cloneInventory(opts.State)}
+}
+
+// State 最後に取得した状態の複製。保存しておき、WatcherOptions.Stateに渡すと続きから監視できる
+func (w *Watcher) State() *Inventory {
+	return cloneInventory(w.current())
+}
+
+// current 最後に取得した状態。書き換えずに置き換えるので、複製せずに読んでよい
+func (w *Watcher) current() *Inventory {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// Watch 監視を始める。ctxが終わるとチャネルを閉じる
+func (w *Watcher) Watch(ctx context.Context) <-chan Event {
+	ch := make(chan Event, w.opts.Buffer)
+	go func() {
+		defer close(ch)
+		for {
+			interval := w.opts.Interval
+			if err := w.poll(ctx, ch); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if w.opts.OnError != nil {
+					w.opts.OnError(err)
+				}
+				interval = w.opts.ErrorInterval
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return ch
+}
+
+// poll 一覧を取得し、前回からの変化を通知する
+func (w *Watcher) poll(ctx context.Context, ch chan<- Event) error {
+	current, err := snapshot(ctx, w.client, false)
+	if err != nil {
+		return err
+	}
+
+	// 通知を終えてから状態を進め、途中で止まっても次回に同じ変化を通知できるようにする
+	if prev := w.current(); prev != nil {
+		carryOver(prev, current)
+		now := time.Now().UTC()
+		for _, r := range DiffInventory(prev, current, DiffOptions{}).Resources {
+			for _, e := range eventsOf(&r) {
+				e.Time = now
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+
+	w.mu.Lock()
+	w.state = current
+	w.mu.Unlock()
+	return nil
+}
+
+// carryOver 利用可能でないパーティションのクライアントとピアは取得できないため、前回の状態を引き継ぐ
+//
+// ライセンスは取得しないので、これも前回の状態を引き継ぐ。
+func carryOver(prev, current *Inventory) {
+	current.Licenses = slices.Clone(nonNil(prev.Licenses))
+
+	index := map[string]*PartitionRecord{}
+	for i := range prev.Partitions {
+		index[prev.Partitions[i].ID] = &prev.Partitions[i]
+	}
+	for i := range current.Partitions {
+		p := &current.Partitions[i]
+		if p.Availability == string(v1.AvailabilityEnumAvailable) {
+			continue
+		}
+		if old, ok := index[p.ID]; ok {
+			p.Clients, p.Peers = old.Clients, old.Peers
+		}
+	}
+}
+
+// eventsOf 差分をEventに変換する。利用可否・状態の変化はそれ以外の変化と分けて通知する
+func eventsOf(r *ResourceDiff) []Event {
+	base := Event{Resource: r.Resource, ID: r.ID, Name: r.Name, Partition: r.Partition}
+	switch r.Kind {
+	case ChangeAdded:
+		base.Type = EventCreated
+		return []Event{base}
+	case ChangeRemoved:
+		base.Type = EventDeleted
+		return []Event{base}
+	}
+
+	var ret []Event
+	var rest []FieldChange
+	for _, c := range r.Changes {
+		switch {
+		case c.Field == "Availability":
+			e := base
+			e.Type = EventAvailabilityChanged
+			e.Changes = []FieldChange{c}
+			ret = append(ret, e)
+		case c.Field == "Status" && r.Resource == "Peer":
+			e := base
+			e.Type = EventPeerStatusChanged
+			e.Changes = []FieldChange{c}
+			ret = append(ret, e)
+		default:
+			rest = append(rest, c)
+		}
+	}
+	if len(rest) > 0 {
+		e := base
+		e.Type = EventUpdated
+		e.Changes = rest
+		ret = append(ret, e)
+	}
+	return ret
+}
@@ -0,0 +1,198 @@
+// Copyright 2025- The sacloud/cloudhsm-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudhsm_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/sacloud/cloudhsm-api-go"
+	v1 "github.com/sacloud/cloudhsm-api-go/apis/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// watchServer serves a single partition whose state the test changes between polls.
+type watchServer struct {
+	mu      sync.Mutex
+	hsm     v1.CloudHSM
+	clients []v1.CloudHSMClient
+	peers   []v1.CloudHSMPeer
+
+	licenses []v1.CloudHSMSoftwareLicense
+}
+
+func (s *watchServer) update(f func(s *watchServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func (s *watchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch p := strings.TrimSuffix(r.URL.Path, "/"); {
+	case strings.HasSuffix(p, "/peers"):
+		respondJSON(w, http.StatusOK, v1.CloudHSMPeerList{Peers: append([]v1.CloudHSMPeer{}, s.peers...)})
+	case strings.HasSuffix(p, "/clients"):
+		respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMClientList{
+			Count: len(s.clients), From: v1.NewOptInt(0), Total: v1.NewOptInt(len(s.clients)),
+			Clients: append([]v1.CloudHSMClient{}, s.clients...),
+		})
+	case strings.HasSuffix(p, "/licenses"):
+		respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMSoftwareLicenseList{
+			Count: len(s.licenses), From: v1.NewOptInt(0), Total: v1.NewOptInt(len(s.licenses)),
+			Licenses: append([]v1.CloudHSMSoftwareLicense{}, s.licenses...),
+		})
+	case strings.HasSuffix(p, "/cloudhsms"):
+		respondJSON(w, http.StatusOK, v1.PaginatedCloudHSMList{
+			Count: 1, From: v1.NewOptInt(0), Total: v1.NewOptInt(1), CloudHSMs: []v1.CloudHSM{s.hsm},
+		})
+	default:
+		respondJSON(w, http.StatusNotFound, newErrorResponse("not found"))
+	}
+}
+
+func nextEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e, ok := <-ch:
+		require.True(t, ok, "channel closed")
+		e.Time = time.Time{}
+		return e
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return Event{}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	assert := require.New(t)
+
+	srv := &watchServer{hsm: TemplateCloudHSM}
+	srv.hsm.SetID("hsm-1")
+	srv.hsm.SetName("hsm")
+	srv.hsm.SetAvailability(v1.AvailabilityEnumPrecreate)
+	client := newTestClientWithHandler(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := NewWatcher(client, WatcherOptions{Interval: 10 * time.Millisecond})
+	ch := w.Watch(ctx)
+	assert.Eventually(func() bool { return w.State() != nil }, 10*time.Second, 10*time.Millisecond)
+
+	c := TemplateCloudHSMClient
+	c.SetID("client-1")
+	c.SetName("app")
+	srv.update(func(s *watchServer) {
+		s.hsm.SetAvailability(v1.AvailabilityEnumAvailable)
+		s.clients = []v1.CloudHSMClient{c}
+		s.peers = []v1.CloudHSMPeer{{ID: "router-1", Status: v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusUP), Routes: []string{}}}
+	})
+
+	assert.Equal(Event{
+		Type: EventAvailabilityChanged, Resource: "Partition", ID: "hsm-1", Name: "hsm",
+		Changes: []FieldChange{{Field: "Availability", Old: "precreate", New: "available"}},
+	}, nextEvent(t, ch))
+	assert.Equal(Event{Type: EventCreated, Resource: "Client", ID: "client-1", Name: "app", Partition: "hsm-1"}, nextEvent(t, ch))
+	assert.Equal(Event{Type: EventCreated, Resource: "Peer", ID: "router-1", Partition: "hsm-1"}, nextEvent(t, ch))
+
+	srv.update(func(s *watchServer) {
+		s.peers[0].SetStatus(v1.NewOptCloudHSMPeerStatus(v1.CloudHSMPeerStatusDOWN))
+	})
+	assert.Equal(Event{
+		Type: EventPeerStatusChanged, Resource: "Peer", ID: "router-1", Partition: "hsm-1",
+		Changes: []FieldChange{{Field: "Status", Old: "UP", New: "DOWN"}},
+	}, nextEvent(t, ch))
+
+	cancel()
+	for range ch {
+	}
+	state := w.State()
+
+	// Changes made while stopped are reported when resuming from the saved state.
+	srv.update(func(s *watchServer) {
+		s.hsm.SetName("renamed")
+		s.clients = nil
+	})
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	ch = NewWatcher(client, WatcherOptions{Interval: time.Hour, State: state}).Watch(ctx)
+	assert.Equal(Event{
+		Type: EventUpdated, Resource: "Partition", ID: "hsm-1", Name: "renamed",
+		Changes: []FieldChange{{Field: "Name", Old: "hsm", New: "renamed"}},
+	}, nextEvent(t, ch))
+	assert.Equal(Event{Type: EventDeleted, Resource: "Client", ID: "client-1", Name: "app", Partition: "hsm-1"}, nextEvent(t, ch))
+}
+
+func TestWatcher_ResumeFromSnapshot(t *testing.T) {
+	assert := require.New(t)
+
+	srv := &watchServer{hsm: TemplateCloudHSM, licenses: []v1.CloudHSMSoftwareLicense{TemplateLicense}}
+	srv.hsm.SetID("hsm-1")
+	srv.hsm.SetName("hsm")
+	srv.hsm.SetAvailability(v1.AvailabilityEnumPrecreate)
+	client := newTestClientWithHandler(t, srv)
+
+	state, err := Snapshot(context.Background(), client)
+	assert.NoError(err)
+	assert.Len(state.Licenses, 1)
+
+	srv.update(func(s *watchServer) {
+		s.hsm.SetName("renamed")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := NewWatcher(client, WatcherOptions{Interval: time.Hour, State: state, Buffer: 10})
+	ch := w.Watch(ctx)
+	assert.Eventually(func() bool { return w.State().Partitions[0].Name == "renamed" }, 10*time.Second, 10*time.Millisecond)
+	cancel()
+
+	// licenses are not watched, so resuming must not report them as deleted
+	var events []Event
+	for e := range ch {
+		e.Time = time.Time{}
+		events = append(events, e)
+	}
+	assert.Equal([]Event{{
+		Type: EventUpdated, Resource: "Partition", ID: "hsm-1", Name: "renamed",
+		Changes: []FieldChange{{Field: "Name", Old: "hsm", New: "renamed"}},
+	}}, events)
+	assert.Equal(state.Licenses, w.State().Licenses)
+}
+
+func TestWatcher_StateIsCopy(t *testing.T) {
+	assert := require.New(t)
+
+	srv := &watchServer{hsm: TemplateCloudHSM}
+	srv.hsm.SetID("hsm-1")
+	srv.hsm.SetName("hsm")
+	srv.hsm.SetTags([]string{"tag1"})
+	client := newTestClientWithHandler(t, srv)
+
+	state, err := Snapshot(context.Background(), client)
+	assert.NoError(err)
+	w := NewWatcher(client, WatcherOptions{State: state})
+
+	// neither the given state nor a returned one is shared with the watcher
+	state.Partitions[0].Tags[0] = "changed"
+	got := w.State()
+	assert.Equal([]string{"tag1"}, got.Partitions[0].Tags)
+	got.Partitions[0].Name = "changed"
+	assert.Equal("hsm", w.State().Partitions[0].Name)
+}